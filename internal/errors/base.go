@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // The strings representing each type of neo4go native error
 const (
@@ -9,6 +12,11 @@ const (
 	DecodingErrorTypeName    = "Decoding"
 	QueryErrorTypeName       = "Query"
 	TransactionErrorTypeName = "Transaction"
+	ContextErrorTypeName     = "Context"
+	ParameterErrorTypeName   = "Parameter"
+	BatchErrorTypeName       = "Batch"
+	ShutdownErrorTypeName    = "Shutdown"
+	EncodingErrorTypeName    = "Encoding"
 	UnknownErrorTypeName     = "Unknown"
 )
 
@@ -100,6 +108,9 @@ func (err *QueryError) FmtError() string {
 type TransactionError struct {
 	// The basic error string
 	Err string
+
+	// The error that caused the transaction to fail, such as the error returned by the work of a managed transaction, if any
+	Cause error
 }
 
 // Error returns the raw error string
@@ -112,6 +123,164 @@ func (err *TransactionError) FmtError() string {
 	return errorFmt(TransactionErrorTypeName, err.Error())
 }
 
+// Unwrap returns the error that caused the transaction to fail
+func (err *TransactionError) Unwrap() error {
+	return err.Cause
+}
+
+/* ----- CONTEXT ERROR ----- */
+
+// ContextError represents an error occurring when the context of an operation is cancelled or expires before the operation ends
+type ContextError struct {
+	// The basic error string
+	Err string
+
+	// The error of the context, either context.Canceled or context.DeadlineExceeded
+	Cause error
+}
+
+// Error returns the raw error string
+func (err *ContextError) Error() string {
+	return err.Err
+}
+
+// FmtError returns the formatted error string
+func (err *ContextError) FmtError() string {
+	return errorFmt(ContextErrorTypeName, err.Error())
+}
+
+// Unwrap returns the error of the context
+func (err *ContextError) Unwrap() error {
+	return err.Cause
+}
+
+/* ----- PARAMETER ERROR ----- */
+
+// ParameterError represents an error occurring when the parameters of a query do not match its placeholders
+type ParameterError struct {
+	// The basic error string
+	Err string
+
+	// The names of the placeholders that have no matching parameter
+	Missing []string
+}
+
+// Error returns the raw error string
+func (err *ParameterError) Error() string {
+	return fmt.Sprintf("%s (Missing : %v)", err.Err, err.Missing)
+}
+
+// FmtError returns the formatted error string
+func (err *ParameterError) FmtError() string {
+	return errorFmt(ParameterErrorTypeName, err.Error())
+}
+
+/* ----- BATCH ERROR ----- */
+
+// BatchError represents an error occurring on one of the statements of a batch, which rolled back the whole batch
+type BatchError struct {
+	// The basic error string
+	Err string
+
+	// The index of the statement that failed in the batch
+	Index int
+
+	// The error returned by the statement
+	Cause error
+}
+
+// Error returns the raw error string
+func (err *BatchError) Error() string {
+	return fmt.Sprintf("%s (Statement : %d / Cause : %v)", err.Err, err.Index, err.Cause)
+}
+
+// FmtError returns the formatted error string
+func (err *BatchError) FmtError() string {
+	return errorFmt(BatchErrorTypeName, err.Error())
+}
+
+// Unwrap returns the error returned by the statement
+func (err *BatchError) Unwrap() error {
+	return err.Cause
+}
+
+/* ----- SHUTDOWN ERROR ----- */
+
+// ShutdownError represents an error occurring because the manager is shut down, or while shutting it down
+type ShutdownError struct {
+	// The basic error string
+	Err string
+
+	// The errors of every transaction, session or driver that could not be released during the shutdown
+	Failures []error
+}
+
+// Error returns the raw error string
+func (err *ShutdownError) Error() string {
+	if len(err.Failures) == 0 {
+		return err.Err
+	}
+
+	failures := make([]string, 0, len(err.Failures))
+	for _, failure := range err.Failures {
+		failures = append(failures, failure.Error())
+	}
+
+	return fmt.Sprintf("%s (Failures : [%s])", err.Err, strings.Join(failures, " / "))
+}
+
+// FmtError returns the formatted error string
+func (err *ShutdownError) FmtError() string {
+	return errorFmt(ShutdownErrorTypeName, err.Error())
+}
+
+// Unwrap returns the errors of every resource that could not be released
+func (err *ShutdownError) Unwrap() []error {
+	return err.Failures
+}
+
+/* ----- ENCODING ERROR ----- */
+
+// EncodingFailure represents a single value that could not be encoded
+type EncodingFailure struct {
+	// The path of the value in the encoded object, as in User.Address.Geo or Tags[3]
+	Path string
+
+	// The Go type of the value
+	GotType string
+
+	// The error returned by the marshaler of the value, if it has one
+	Err string
+}
+
+// EncodingError represents an error occurring when some values of an object cannot be encoded into query inputs
+type EncodingError struct {
+	// The basic error string
+	Err string
+
+	// Every value that could not be encoded
+	Failures []EncodingFailure
+}
+
+// Error returns the raw error string
+func (err *EncodingError) Error() string {
+	failures := make([]string, 0, len(err.Failures))
+	for _, failure := range err.Failures {
+		if failure.Err != "" {
+			failures = append(failures, fmt.Sprintf("%s (%s : %s)", failure.Path, failure.GotType, failure.Err))
+		} else {
+			failures = append(failures, fmt.Sprintf("%s (%s)", failure.Path, failure.GotType))
+		}
+	}
+
+	return fmt.Sprintf("%s (Failures : [%s])", err.Err, strings.Join(failures, " / "))
+}
+
+// FmtError returns the formatted error string
+func (err *EncodingError) FmtError() string {
+	return errorFmt(EncodingErrorTypeName, err.Error())
+}
+
 /* ----- UNKOWN ERROR ----- */
 
 // UnknownError represents any error not known by the neo4go package
@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"context"
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -372,6 +374,376 @@ func TestTransactionError_FmtError(t *testing.T) {
 	}
 }
 
+func TestTransactionError_Unwrap(t *testing.T) {
+	cause := fmt.Errorf("A typical cause")
+
+	tests := []struct {
+		name string
+		err  *TransactionError
+		want error
+	}{
+		{
+			name: "Should return the cause of the error",
+			err: &TransactionError{
+				Err:   "A typical error",
+				Cause: cause,
+			},
+			want: cause,
+		},
+		{
+			name: "Should return nil without a cause",
+			err: &TransactionError{
+				Err: "A typical error",
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Unwrap(); got != tt.want {
+				t.Errorf("TransactionError.Unwrap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ContextError
+		want string
+	}{
+		{
+			name: "Should contain raw error string",
+			err: &ContextError{
+				Err: "A typical error",
+			},
+			want: "A typical error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); !strings.Contains(got, tt.want) {
+				t.Errorf("ContextError.Error() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextError_FmtError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ContextError
+		want string
+	}{
+		{
+			name: "Should contain error type name",
+			err: &ContextError{
+				Err: "A typical error",
+			},
+			want: ContextErrorTypeName,
+		},
+		{
+			name: "Should contain raw error string",
+			err: &ContextError{
+				Err: "A typical error",
+			},
+			want: "A typical error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.FmtError(); !strings.Contains(got, tt.want) {
+				t.Errorf("ContextError.FmtError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextError_Unwrap(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ContextError
+		want error
+	}{
+		{
+			name: "Should return the error of the context",
+			err: &ContextError{
+				Err:   "A typical error",
+				Cause: context.DeadlineExceeded,
+			},
+			want: context.DeadlineExceeded,
+		},
+		{
+			name: "Should return nil without an error of the context",
+			err: &ContextError{
+				Err: "A typical error",
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Unwrap(); got != tt.want {
+				t.Errorf("ContextError.Unwrap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParameterError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ParameterError
+		want string
+	}{
+		{
+			name: "Should contain raw error string",
+			err: &ParameterError{
+				Err: "A typical error",
+			},
+			want: "A typical error",
+		},
+		{
+			name: "Should contain missing parameters",
+			err: &ParameterError{
+				Err:     "A typical error",
+				Missing: []string{"name"},
+			},
+			want: "name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); !strings.Contains(got, tt.want) {
+				t.Errorf("ParameterError.Error() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParameterError_FmtError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ParameterError
+		want string
+	}{
+		{
+			name: "Should contain error type name",
+			err: &ParameterError{
+				Err: "A typical error",
+			},
+			want: ParameterErrorTypeName,
+		},
+		{
+			name: "Should contain raw error string",
+			err: &ParameterError{
+				Err: "A typical error",
+			},
+			want: "A typical error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.FmtError(); !strings.Contains(got, tt.want) {
+				t.Errorf("ParameterError.FmtError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *BatchError
+		want string
+	}{
+		{
+			name: "Should contain raw error string",
+			err: &BatchError{
+				Err: "A typical error",
+			},
+			want: "A typical error",
+		},
+		{
+			name: "Should contain the cause",
+			err: &BatchError{
+				Err:   "A typical error",
+				Index: 2,
+				Cause: &QueryError{Err: "A cause"},
+			},
+			want: "A cause",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); !strings.Contains(got, tt.want) {
+				t.Errorf("BatchError.Error() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchError_FmtError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *BatchError
+		want string
+	}{
+		{
+			name: "Should contain error type name",
+			err: &BatchError{
+				Err: "A typical error",
+			},
+			want: BatchErrorTypeName,
+		},
+		{
+			name: "Should contain raw error string",
+			err: &BatchError{
+				Err: "A typical error",
+			},
+			want: "A typical error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.FmtError(); !strings.Contains(got, tt.want) {
+				t.Errorf("BatchError.FmtError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShutdownError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ShutdownError
+		want string
+	}{
+		{
+			name: "Should contain raw error string",
+			err: &ShutdownError{
+				Err: "A typical error",
+			},
+			want: "A typical error",
+		},
+		{
+			name: "Should contain every failure",
+			err: &ShutdownError{
+				Err:      "A typical error",
+				Failures: []error{&TransactionError{Err: "A first failure"}, &UnknownError{Err: "A second failure"}},
+			},
+			want: "A first failure / A second failure",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); !strings.Contains(got, tt.want) {
+				t.Errorf("ShutdownError.Error() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShutdownError_FmtError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ShutdownError
+		want string
+	}{
+		{
+			name: "Should contain error type name",
+			err: &ShutdownError{
+				Err: "A typical error",
+			},
+			want: ShutdownErrorTypeName,
+		},
+		{
+			name: "Should contain raw error string",
+			err: &ShutdownError{
+				Err: "A typical error",
+			},
+			want: "A typical error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.FmtError(); !strings.Contains(got, tt.want) {
+				t.Errorf("ShutdownError.FmtError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodingError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *EncodingError
+		want string
+	}{
+		{
+			name: "Should contain raw error string",
+			err: &EncodingError{
+				Err: "A typical error",
+			},
+			want: "A typical error",
+		},
+		{
+			name: "Should contain every failure with its type",
+			err: &EncodingError{
+				Err:      "A typical error",
+				Failures: []EncodingFailure{{Path: "User.Address.Geo", GotType: "chan int"}, {Path: "Tags[3]", GotType: "func()"}},
+			},
+			want: "User.Address.Geo (chan int) / Tags[3] (func())",
+		},
+		{
+			name: "Should contain the error of a marshaler",
+			err: &EncodingError{
+				Err:      "A typical error",
+				Failures: []EncodingFailure{{Path: "User.Role", GotType: "Role", Err: "unknown role"}},
+			},
+			want: "User.Role (Role : unknown role)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); !strings.Contains(got, tt.want) {
+				t.Errorf("EncodingError.Error() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodingError_FmtError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *EncodingError
+		want string
+	}{
+		{
+			name: "Should contain error type name",
+			err: &EncodingError{
+				Err: "A typical error",
+			},
+			want: EncodingErrorTypeName,
+		},
+		{
+			name: "Should contain raw error string",
+			err: &EncodingError{
+				Err: "A typical error",
+			},
+			want: "A typical error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.FmtError(); !strings.Contains(got, tt.want) {
+				t.Errorf("EncodingError.FmtError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestUnknownError_Error(t *testing.T) {
 	tests := []struct {
 		name string
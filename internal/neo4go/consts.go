@@ -1,7 +1,28 @@
 package neo4go
 
+import "time"
+
 // The default tag names for encoding/decoding neo4j values
 const (
 	DefaultEncodingTagName = "neo4j"
 	DefaultDecodingTagName = "neo4j"
 )
+
+// The default retry configuration of managed transactions
+const (
+	DefaultRetryMaxRetries   = 5
+	DefaultRetryInitialDelay = 1 * time.Second
+	DefaultRetryMaxDelay     = 30 * time.Second
+	DefaultRetryMultiplier   = 2.0
+	DefaultRetryJitter       = 0.2
+)
+
+// The default time for which closing a manager waits for its running operations
+const DefaultShutdownTimeout = 30 * time.Second
+
+// The default configuration of bulk writers
+const (
+	DefaultBulkChunkSize = 1000
+	DefaultBulkWorkers   = 1
+	BulkRowsParamName    = "rows"
+)
@@ -0,0 +1,94 @@
+package neo4go
+
+import "sync"
+
+// BookmarkManager keeps the bookmarks of a causal chain of queries and transactions.
+// Every query or transaction given the same bookmark manager sees the writes of the previous ones,
+// while independent chains do not wait for each other. It is safe for concurrent use
+type BookmarkManager interface {
+	// Bookmarks returns the bookmarks that the next query or transaction of the chain must wait for
+	Bookmarks() []string
+
+	// UpdateBookmarks replaces the bookmarks used by a query or transaction with the bookmark it obtained.
+	// An empty new bookmark leaves the chain unchanged
+	UpdateBookmarks(previous []string, new string)
+}
+
+// bookmarkManager is the default implementation of the BookmarkManager interface
+type bookmarkManager struct {
+	// The current bookmarks of the chain
+	bookmarks []string
+
+	// The mutex used to prevent concurrent access to the bookmarks
+	mutex sync.RWMutex
+}
+
+// NewBookmarkManager creates a new instance of BookmarkManager, starting its chain after the given bookmarks
+func NewBookmarkManager(bookmarks ...string) BookmarkManager {
+	newManager := &bookmarkManager{bookmarks: make([]string, 0, len(bookmarks))}
+	for _, bookmark := range bookmarks {
+		newManager.UpdateBookmarks(nil, bookmark)
+	}
+
+	return newManager
+}
+
+// Bookmarks returns the bookmarks that the next query or transaction of the chain must wait for
+func (bm *bookmarkManager) Bookmarks() []string {
+	bm.mutex.RLock()
+	defer bm.mutex.RUnlock()
+
+	bookmarks := make([]string, len(bm.bookmarks))
+	copy(bookmarks, bm.bookmarks)
+
+	return bookmarks
+}
+
+// UpdateBookmarks replaces the bookmarks used by a query or transaction with the bookmark it obtained.
+// The bookmarks added by concurrent queries of the chain in the meantime are kept
+func (bm *bookmarkManager) UpdateBookmarks(previous []string, new string) {
+	if new == "" {
+		return
+	}
+
+	bm.mutex.Lock()
+	defer bm.mutex.Unlock()
+
+	replaced := make(map[string]bool, len(previous)+1)
+	for _, bookmark := range previous {
+		replaced[bookmark] = true
+	}
+	replaced[new] = true
+
+	bookmarks := make([]string, 0, len(bm.bookmarks)+1)
+	for _, bookmark := range bm.bookmarks {
+		if !replaced[bookmark] {
+			bookmarks = append(bookmarks, bookmark)
+		}
+	}
+	bm.bookmarks = append(bookmarks, new)
+}
+
+// sessionBookmarks returns the bookmarks that a new session must wait for: the given ones, then the ones of the bookmark manager
+func sessionBookmarks(bookmarks []string, bookmarkManager BookmarkManager) []string {
+	if bookmarkManager == nil {
+		return bookmarks
+	}
+
+	allBookmarks := make([]string, 0, len(bookmarks))
+	allBookmarks = append(allBookmarks, bookmarks...)
+	for _, bookmark := range bookmarkManager.Bookmarks() {
+		isDuplicate := false
+		for _, existing := range bookmarks {
+			if existing == bookmark {
+				isDuplicate = true
+				break
+			}
+		}
+		if !isDuplicate {
+			allBookmarks = append(allBookmarks, bookmark)
+		}
+	}
+
+	return allBookmarks
+}
@@ -0,0 +1,81 @@
+package neo4go
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_bookmarkManager_UpdateBookmarks(t *testing.T) {
+	type args struct {
+		previous []string
+		new      string
+	}
+	tests := []struct {
+		name    string
+		initial []string
+		args    args
+		want    []string
+	}{
+		{
+			name:    "Should replace the used bookmarks with the new one",
+			initial: []string{"a", "b"},
+			args:    args{previous: []string{"a", "b"}, new: "c"},
+			want:    []string{"c"},
+		},
+		{
+			name:    "Should keep the bookmarks added by concurrent queries",
+			initial: []string{"a", "b"},
+			args:    args{previous: []string{"a"}, new: "c"},
+			want:    []string{"b", "c"},
+		},
+		{
+			name:    "Should ignore an empty bookmark",
+			initial: []string{"a"},
+			args:    args{previous: []string{"a"}, new: ""},
+			want:    []string{"a"},
+		},
+		{
+			name:    "Should not duplicate bookmarks",
+			initial: []string{"a", "a"},
+			args:    args{previous: nil, new: "a"},
+			want:    []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bm := NewBookmarkManager(tt.initial...)
+			bm.UpdateBookmarks(tt.args.previous, tt.args.new)
+			if got := bm.Bookmarks(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bookmarkManager.Bookmarks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sessionBookmarks(t *testing.T) {
+	tests := []struct {
+		name            string
+		bookmarks       []string
+		bookmarkManager BookmarkManager
+		want            []string
+	}{
+		{
+			name:      "Should keep the given bookmarks without bookmark manager",
+			bookmarks: []string{"a"},
+			want:      []string{"a"},
+		},
+		{
+			name:            "Should add the bookmarks of the bookmark manager without duplicates",
+			bookmarks:       []string{"a"},
+			bookmarkManager: NewBookmarkManager("a", "b"),
+			want:            []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sessionBookmarks(tt.bookmarks, tt.bookmarkManager); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sessionBookmarks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package neo4go
+
+import "github.com/neo4j/neo4j-go-driver/neo4j"
+
+// bufferedResult is an implementation of neo4j.Result whose records were all fetched in memory,
+// so that they stay readable after the session that ran the query is closed
+type bufferedResult struct {
+	// The keys of the result records
+	keys []string
+
+	// All the records of the result
+	records []neo4j.Record
+
+	// The summary of the query execution
+	summary neo4j.ResultSummary
+
+	// The index of the current record, which is -1 before the first call to Next
+	currentIndex int
+}
+
+// newBufferedResult fetches all the records of the given result and returns them as a new neo4j.Result
+func newBufferedResult(result neo4j.Result) (neo4j.Result, error) {
+	keys, err := result.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]neo4j.Record, 0)
+	for result.Next() {
+		records = append(records, result.Record())
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+
+	summary, err := result.Consume()
+	if err != nil {
+		return nil, err
+	}
+
+	return &bufferedResult{
+		keys:         keys,
+		records:      records,
+		summary:      summary,
+		currentIndex: -1,
+	}, nil
+}
+
+// Keys returns the keys available on the result set.
+func (res *bufferedResult) Keys() ([]string, error) {
+	return res.keys, nil
+}
+
+// Next returns true only if there is a record to be processed.
+func (res *bufferedResult) Next() bool {
+	if res.currentIndex < len(res.records) {
+		res.currentIndex++
+	}
+
+	return res.currentIndex < len(res.records)
+}
+
+// Err returns the latest error that caused this Next to return false.
+func (res *bufferedResult) Err() error {
+	return nil
+}
+
+// Record returns the current record.
+func (res *bufferedResult) Record() neo4j.Record {
+	if res.currentIndex < 0 || res.currentIndex >= len(res.records) {
+		return nil
+	}
+
+	return res.records[res.currentIndex]
+}
+
+// Summary returns the summary information about the statement execution.
+func (res *bufferedResult) Summary() (neo4j.ResultSummary, error) {
+	return res.summary, nil
+}
+
+// Consume consumes the entire result and returns the summary information
+// about the statement execution.
+func (res *bufferedResult) Consume() (neo4j.ResultSummary, error) {
+	res.currentIndex = len(res.records)
+	return res.summary, nil
+}
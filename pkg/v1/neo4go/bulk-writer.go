@@ -0,0 +1,450 @@
+package neo4go
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
+	internalMain "github.com/UlysseGuyon/neo4go/internal/neo4go"
+)
+
+// BulkWriter encodes large amounts of rows and writes them in chunks, each chunk being given to a single UNWIND query.
+// It is safe for concurrent use
+type BulkWriter interface {
+	// Write encodes the rows and buffers them all, or none of them if one cannot be encoded. Every full chunk is handed to the flush workers,
+	// so this call blocks while all the workers are busy
+	Write(rows ...interface{}) Neo4GoError
+
+	// WriteContext is the same as Write, but gives up waiting for a free worker if the context is done.
+	// The rows are buffered anyway, and the chunks that could not be handed are handed by the next call.
+	// The context is also applied to the writing of the chunks handed by this call
+	WriteContext(ctx context.Context, rows ...interface{}) Neo4GoError
+
+	// Flush hands the buffered rows to the flush workers as a chunk, even if it is not full
+	Flush() Neo4GoError
+
+	// FlushContext is the same as Flush, but gives up waiting for a free worker if the context is done
+	FlushContext(context.Context) Neo4GoError
+
+	// Close flushes the buffered rows, waits for every chunk to be written, then returns the report of all the chunks.
+	// The returned error is the one of the first chunk that failed, if any
+	Close() (BulkReport, Neo4GoError)
+
+	// CloseContext is the same as Close, but the context is applied to the writing of the last chunks.
+	// The chunks that could not be handed to a worker before the context is done are reported as failed
+	CloseContext(context.Context) (BulkReport, Neo4GoError)
+}
+
+// BulkWriterOptions represents the configuration applied to a bulk writer
+type BulkWriterOptions struct {
+	// The cypher query run for every chunk. It receives the rows of the chunk as the $rows param, as in "UNWIND $rows AS row CREATE (n:Node) SET n = row"
+	Query string
+
+	// The other parameters given to the query of every chunk
+	Params map[string]InputStruct
+
+	// The configuration of the transaction of every chunk. It is always a write transaction
+	TransactionParams TransactionParams
+
+	// The maximum number of rows written by a single query. Zero means the default chunk size (1000)
+	ChunkSize int
+
+	// The number of chunks written in parallel. Zero means a single worker
+	Workers int
+
+	// The retry configuration applied to every chunk on temporary failures
+	Retry RetryOptions
+
+	// The encoder used to encode the rows. By default, a silent encoder with the default options is used
+	Encoder Encoder
+}
+
+// BulkChunkReport represents the outcome of the writing of a single chunk
+type BulkChunkReport struct {
+	// The position of the chunk among all the chunks of the writer, starting at 0
+	Index int
+
+	// The number of rows in the chunk
+	Rows int
+
+	// The number of times the chunk query was run, including the retries
+	Attempts int
+
+	// The error that made the chunk fail for good, or nil if the chunk was written
+	Err Neo4GoError
+}
+
+// BulkReport represents the outcome of all the chunks of a bulk writer
+type BulkReport struct {
+	// The report of every chunk, ordered by index
+	Chunks []BulkChunkReport
+
+	// The number of rows of the chunks that were written
+	RowsWritten int
+
+	// The number of rows of the chunks that failed
+	RowsFailed int
+}
+
+// bulkChunk represents a chunk of encoded rows waiting for a flush worker
+type bulkChunk struct {
+	// The context applied to the writing of the chunk
+	ctx context.Context
+
+	// The position of the chunk among all the chunks of the writer
+	index int
+
+	// The encoded rows of the chunk
+	rows []InputStruct
+}
+
+// bulkWriter is the default implementation of the BulkWriter interface
+type bulkWriter struct {
+	// The manager used to write the chunks
+	manager Manager
+
+	// The configuration of this writer, with its default values set
+	options BulkWriterOptions
+
+	// The encoded rows that do not fill a chunk yet
+	buffer []InputStruct
+
+	// The chunks that are full, or flushed, but not handed to a worker yet, in order
+	pending []bulkChunk
+
+	// The number of chunks created so far
+	chunkCount int
+
+	// Tells if the writer was closed
+	closed bool
+
+	// Waits for every call that is handing chunks to the workers, so that the chunk channel is only closed after them
+	senders sync.WaitGroup
+
+	// The channel through which the chunks are handed to the workers
+	chunks chan bulkChunk
+
+	// Waits for every worker to stop
+	workers sync.WaitGroup
+
+	// The reports of the chunks written so far
+	reports []BulkChunkReport
+
+	// The mutex used to prevent concurrent access to the buffer, the pending chunks and the closed state.
+	// It is never held while waiting for a worker, so that a slow chunk does not block the other calls
+	mutex sync.Mutex
+
+	// The mutex used to prevent concurrent access to the reports
+	reportsMutex sync.Mutex
+}
+
+// NewBulkWriter creates a new instance of BulkWriter that writes its chunks through the given manager.
+// The query of the options must use the $rows param
+func NewBulkWriter(manager Manager, opt BulkWriterOptions) (BulkWriter, Neo4GoError) {
+	isRowsUsed := false
+	for _, name := range queryParameters(tokenizeCypher(opt.Query)) {
+		if name == internalMain.BulkRowsParamName {
+			isRowsUsed = true
+		}
+	}
+	if !isRowsUsed {
+		return nil, &internalErr.ParameterError{
+			Err:     "The query of a bulk writer must use the rows of its chunks",
+			Missing: []string{internalMain.BulkRowsParamName},
+		}
+	}
+	if _, exists := opt.Params[internalMain.BulkRowsParamName]; exists {
+		return nil, &internalErr.QueryError{
+			Err: "The rows param of a bulk writer query is reserved for the rows of its chunks",
+		}
+	}
+
+	// Set the default values of the options
+	if opt.ChunkSize <= 0 {
+		opt.ChunkSize = internalMain.DefaultBulkChunkSize
+	}
+	if opt.Workers <= 0 {
+		opt.Workers = internalMain.DefaultBulkWorkers
+	}
+	if opt.Encoder == nil {
+		opt.Encoder = NewEncoder(&EncoderOptions{Silent: true})
+	}
+	opt.Retry = opt.Retry.withDefaults()
+	opt.TransactionParams.IsWrite = true
+
+	newWriter := &bulkWriter{
+		manager: manager,
+		options: opt,
+		buffer:  make([]InputStruct, 0, opt.ChunkSize),
+		chunks:  make(chan bulkChunk),
+		reports: make([]BulkChunkReport, 0),
+	}
+
+	newWriter.workers.Add(opt.Workers)
+	for i := 0; i < opt.Workers; i++ {
+		go newWriter.work()
+	}
+
+	return newWriter, nil
+}
+
+// Write encodes the rows and buffers them all, or none of them if one cannot be encoded. Every full chunk is handed to the flush workers,
+// so this call blocks while all the workers are busy
+func (w *bulkWriter) Write(rows ...interface{}) Neo4GoError {
+	return w.WriteContext(context.Background(), rows...)
+}
+
+// WriteContext is the same as Write, but gives up waiting for a free worker if the context is done.
+// The rows are buffered anyway, and the chunks that could not be handed are handed by the next call.
+// The context is also applied to the writing of the chunks handed by this call
+func (w *bulkWriter) WriteContext(ctx context.Context, rows ...interface{}) Neo4GoError {
+	// Encode every row before buffering any of them, so that a wrong row does not leave the others half written.
+	// A row with a value that cannot be encoded is refused, instead of being written without this value
+	encodedRows := make([]InputStruct, 0, len(rows))
+	for index, row := range rows {
+		encodedRow, encodeErr := w.options.Encoder.EncodeWithError(row)
+		if encodeErr != nil {
+			if encodingErr, isEncodingErr := encodeErr.(*internalErr.EncodingError); isEncodingErr {
+				return &internalErr.EncodingError{
+					Err:      fmt.Sprintf("Could not encode the row %d of the bulk writer", index),
+					Failures: encodingErr.Failures,
+				}
+			}
+
+			return encodeErr
+		}
+		if encodedRow == nil {
+			gotType := "nil"
+			if row != nil {
+				gotType = reflect.TypeOf(row).String()
+			}
+
+			return &internalErr.TypeError{
+				Err:           fmt.Sprintf("Could not encode the row %d of the bulk writer", index),
+				ExpectedTypes: []string{"struct", "map"},
+				GotType:       gotType,
+			}
+		}
+		encodedRows = append(encodedRows, encodedRow)
+	}
+
+	// Buffer every row at once, then hand the full chunks to the workers without holding the lock
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return closedBulkWriterError()
+	}
+
+	for _, encodedRow := range encodedRows {
+		w.buffer = append(w.buffer, encodedRow)
+		if len(w.buffer) == w.options.ChunkSize {
+			w.cutBuffer()
+		}
+	}
+	w.senders.Add(1)
+	w.mutex.Unlock()
+
+	defer w.senders.Done()
+	return w.sendPending(ctx)
+}
+
+// Flush hands the buffered rows to the flush workers as a chunk, even if it is not full
+func (w *bulkWriter) Flush() Neo4GoError {
+	return w.FlushContext(context.Background())
+}
+
+// FlushContext is the same as Flush, but gives up waiting for a free worker if the context is done
+func (w *bulkWriter) FlushContext(ctx context.Context) Neo4GoError {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return closedBulkWriterError()
+	}
+
+	w.cutBuffer()
+	w.senders.Add(1)
+	w.mutex.Unlock()
+
+	defer w.senders.Done()
+	return w.sendPending(ctx)
+}
+
+// Close flushes the buffered rows, waits for every chunk to be written, then returns the report of all the chunks.
+// The returned error is the one of the first chunk that failed, if any
+func (w *bulkWriter) Close() (BulkReport, Neo4GoError) {
+	return w.CloseContext(context.Background())
+}
+
+// CloseContext is the same as Close, but the context is applied to the writing of the last chunks.
+// The chunks that could not be handed to a worker before the context is done are reported as failed
+func (w *bulkWriter) CloseContext(ctx context.Context) (BulkReport, Neo4GoError) {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return BulkReport{}, closedBulkWriterError()
+	}
+
+	w.cutBuffer()
+	w.closed = true
+	w.mutex.Unlock()
+
+	// No call can hand chunks anymore once the running ones are done, so the last pending chunks are handed by this one
+	w.senders.Wait()
+	sendErr := w.sendPending(ctx)
+	if sendErr != nil {
+		w.mutex.Lock()
+		abandonedChunks := w.pending
+		w.pending = nil
+		w.mutex.Unlock()
+
+		w.reportsMutex.Lock()
+		for _, chunk := range abandonedChunks {
+			w.reports = append(w.reports, BulkChunkReport{Index: chunk.index, Rows: len(chunk.rows), Err: sendErr})
+		}
+		w.reportsMutex.Unlock()
+	}
+
+	close(w.chunks)
+	w.workers.Wait()
+
+	report := w.report()
+	if sendErr != nil {
+		return report, sendErr
+	}
+	for _, chunkReport := range report.Chunks {
+		if chunkReport.Err != nil {
+			return report, chunkReport.Err
+		}
+	}
+
+	return report, nil
+}
+
+// cutBuffer turns the buffered rows into a pending chunk, even if it is not full.
+// The writer mutex must be locked by the caller
+func (w *bulkWriter) cutBuffer() {
+	if len(w.buffer) == 0 {
+		return
+	}
+
+	w.pending = append(w.pending, bulkChunk{index: w.chunkCount, rows: w.buffer})
+	w.chunkCount++
+	w.buffer = make([]InputStruct, 0, w.options.ChunkSize)
+}
+
+// sendPending hands the pending chunks to the workers, with the given context applied to their writing.
+// If the context is done first, the chunk that was not handed goes back to the front of the pending chunks
+func (w *bulkWriter) sendPending(ctx context.Context) Neo4GoError {
+	for {
+		w.mutex.Lock()
+		if len(w.pending) == 0 {
+			w.mutex.Unlock()
+			return nil
+		}
+		chunk := w.pending[0]
+		w.pending = w.pending[1:]
+		w.mutex.Unlock()
+
+		chunk.ctx = ctx
+		select {
+		case w.chunks <- chunk:
+		case <-ctx.Done():
+			w.mutex.Lock()
+			w.pending = append([]bulkChunk{chunk}, w.pending...)
+			w.mutex.Unlock()
+
+			return toContextError(ctx.Err())
+		}
+	}
+}
+
+// work writes the chunks it receives until the writer is closed
+func (w *bulkWriter) work() {
+	defer w.workers.Done()
+
+	for chunk := range w.chunks {
+		chunkReport := w.writeChunk(chunk)
+
+		w.reportsMutex.Lock()
+		w.reports = append(w.reports, chunkReport)
+		w.reportsMutex.Unlock()
+	}
+}
+
+// writeChunk runs the query of the writer with the rows of the chunk, and retries it on temporary failures
+func (w *bulkWriter) writeChunk(chunk bulkChunk) BulkChunkReport {
+	chunkReport := BulkChunkReport{Index: chunk.index, Rows: len(chunk.rows)}
+
+	params := make(map[string]InputStruct, len(w.options.Params)+1)
+	for key, val := range w.options.Params {
+		params[key] = val
+	}
+	params[internalMain.BulkRowsParamName] = NewInputArray(chunk.rows)
+
+	chunkReport.Err = w.options.Retry.run(chunk.ctx, func() Neo4GoError {
+		chunkReport.Attempts++
+		return w.writeChunkOnce(chunk.ctx, params)
+	})
+
+	return chunkReport
+}
+
+// writeChunkOnce runs the query of the writer in a new transaction, then commits it on success or rolls it back on error
+func (w *bulkWriter) writeChunkOnce(ctx context.Context, params map[string]InputStruct) Neo4GoError {
+	tx, err := w.manager.BeginContext(ctx, w.options.TransactionParams)
+	if err != nil {
+		return err
+	}
+
+	_, queryErr := tx.QueryContext(ctx, QueryParams{
+		Query:  w.options.Query,
+		Params: params,
+	})
+	if queryErr != nil {
+		// The transaction may have already been released if the query was aborted.
+		// The rollback must not depend on the context of the chunk, which may be done already
+		_ = tx.RollbackContext(context.Background())
+
+		return queryErr
+	}
+
+	commitErr := tx.CommitContext(ctx)
+	if commitErr != nil && !IsContextError(commitErr) {
+		// A failed commit leaves the transaction in the manager store, so it must still be released
+		_ = tx.RollbackContext(context.Background())
+	}
+
+	return commitErr
+}
+
+// report returns the reports of all the chunks written so far, ordered by index
+func (w *bulkWriter) report() BulkReport {
+	w.reportsMutex.Lock()
+	defer w.reportsMutex.Unlock()
+
+	report := BulkReport{Chunks: make([]BulkChunkReport, len(w.reports))}
+	copy(report.Chunks, w.reports)
+	sort.Slice(report.Chunks, func(i, j int) bool {
+		return report.Chunks[i].Index < report.Chunks[j].Index
+	})
+
+	for _, chunkReport := range report.Chunks {
+		if chunkReport.Err != nil {
+			report.RowsFailed += chunkReport.Rows
+		} else {
+			report.RowsWritten += chunkReport.Rows
+		}
+	}
+
+	return report
+}
+
+// closedBulkWriterError returns the error given when a closed bulk writer is used
+func closedBulkWriterError() Neo4GoError {
+	return &internalErr.QueryError{
+		Err: "Trying to use a closed bulk writer",
+	}
+}
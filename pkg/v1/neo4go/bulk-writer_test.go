@@ -0,0 +1,350 @@
+package neo4go
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
+)
+
+type bulkUser struct {
+	Name string `neo4j:"name"`
+	Age  int    `neo4j:"age"`
+}
+
+func TestNewBulkWriter(t *testing.T) {
+	tests := []struct {
+		name         string
+		opt          BulkWriterOptions
+		wantErr      bool
+		wantParamErr bool
+	}{
+		{
+			name: "Should create a bulk writer whose query uses the rows",
+			opt:  BulkWriterOptions{Query: "UNWIND $rows AS row CREATE (u:User) SET u = row"},
+		},
+		{
+			name:         "Should not create a bulk writer whose query does not use the rows",
+			opt:          BulkWriterOptions{Query: "UNWIND $users AS row CREATE (u:User) SET u = row"},
+			wantErr:      true,
+			wantParamErr: true,
+		},
+		{
+			name: "Should not create a bulk writer whose params override the rows",
+			opt: BulkWriterOptions{
+				Query:  "UNWIND $rows AS row CREATE (u:User) SET u = row",
+				Params: map[string]InputStruct{"rows": NewInputArray(nil)},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newFakeManager()
+
+			writer, err := NewBulkWriter(m, tt.opt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewBulkWriter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && IsParameterError(err) != tt.wantParamErr {
+				t.Errorf("NewBulkWriter() error = %v, want a Parameter error %v", err, tt.wantParamErr)
+			}
+			if writer != nil {
+				_, _ = writer.Close()
+			}
+		})
+	}
+}
+
+func Test_bulkWriter(t *testing.T) {
+	tests := []struct {
+		name            string
+		rows            int
+		chunkSize       int
+		workers         int
+		runErrs         []error
+		wantChunkRows   []int
+		wantAttempts    []int
+		wantRowsWritten int
+		wantRowsFailed  int
+		wantErr         bool
+	}{
+		{
+			name:            "Should write the rows in full chunks, then the remaining rows",
+			rows:            5,
+			chunkSize:       2,
+			workers:         1,
+			wantChunkRows:   []int{2, 2, 1},
+			wantAttempts:    []int{1, 1, 1},
+			wantRowsWritten: 5,
+		},
+		{
+			name:            "Should write the chunks in parallel",
+			rows:            10,
+			chunkSize:       3,
+			workers:         3,
+			wantChunkRows:   []int{3, 3, 3, 1},
+			wantAttempts:    []int{1, 1, 1, 1},
+			wantRowsWritten: 10,
+		},
+		{
+			name:            "Should retry a chunk on temporary failures",
+			rows:            2,
+			chunkSize:       2,
+			workers:         1,
+			runErrs:         []error{&internalErr.TransientError{Err: "deadlock"}, &internalErr.TransientError{Err: "deadlock"}},
+			wantChunkRows:   []int{2},
+			wantAttempts:    []int{3},
+			wantRowsWritten: 2,
+		},
+		{
+			name:           "Should report a chunk that fails for good",
+			rows:           3,
+			chunkSize:      2,
+			workers:        1,
+			runErrs:        []error{errors.New("A typical error")},
+			wantChunkRows:  []int{2, 1},
+			wantAttempts:   []int{1, 1},
+			wantRowsFailed: 2,
+			// The second chunk is still written
+			wantRowsWritten: 1,
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, driver := newFakeManager()
+			driver.runErrs = tt.runErrs
+
+			writer, err := NewBulkWriter(m, BulkWriterOptions{
+				Query:     "UNWIND $rows AS row CREATE (u:User) SET u = row",
+				ChunkSize: tt.chunkSize,
+				Workers:   tt.workers,
+				Retry:     RetryOptions{InitialDelay: time.Millisecond, MaxDelay: time.Millisecond},
+			})
+			if err != nil {
+				t.Fatalf("NewBulkWriter() error = %v", err)
+			}
+
+			for i := 0; i < tt.rows; i++ {
+				if err := writer.Write(bulkUser{Name: "Alice", Age: i}); err != nil {
+					t.Fatalf("BulkWriter.Write() error = %v", err)
+				}
+			}
+
+			report, err := writer.Close()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BulkWriter.Close() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if report.RowsWritten != tt.wantRowsWritten || report.RowsFailed != tt.wantRowsFailed {
+				t.Errorf("BulkWriter.Close() rows written / failed = %d / %d, want %d / %d", report.RowsWritten, report.RowsFailed, tt.wantRowsWritten, tt.wantRowsFailed)
+			}
+			if len(report.Chunks) != len(tt.wantChunkRows) {
+				t.Fatalf("BulkWriter.Close() reported %d chunks, want %d", len(report.Chunks), len(tt.wantChunkRows))
+			}
+			for i, chunkReport := range report.Chunks {
+				if chunkReport.Index != i || chunkReport.Rows != tt.wantChunkRows[i] || chunkReport.Attempts != tt.wantAttempts[i] {
+					t.Errorf("BulkWriter.Close() chunk %d = %+v, want %d rows and %d attempts", i, chunkReport, tt.wantChunkRows[i], tt.wantAttempts[i])
+				}
+			}
+
+			// Every row of the written chunks must have been given to a committed query
+			committedRows := 0
+			for _, session := range driver.sessions {
+				for _, fakeTx := range session.transactions {
+					if !fakeTx.committed {
+						continue
+					}
+					for _, params := range fakeTx.params {
+						rows, isArray := params["rows"].([]interface{})
+						if !isArray {
+							t.Fatalf("query rows param = %T, want an array", params["rows"])
+						}
+						row, isMap := rows[0].(map[string]interface{})
+						if name, isString := row["name"].(*string); !isMap || !isString || *name != "Alice" {
+							t.Errorf("query row = %v, want an encoded user", rows[0])
+						}
+						committedRows += len(rows)
+					}
+				}
+			}
+			if committedRows != tt.wantRowsWritten {
+				t.Errorf("committed queries wrote %d rows, want %d", committedRows, tt.wantRowsWritten)
+			}
+		})
+	}
+}
+
+func Test_bulkWriter_Closed(t *testing.T) {
+	m, _ := newFakeManager()
+
+	writer, err := NewBulkWriter(m, BulkWriterOptions{Query: "UNWIND $rows AS row CREATE (u:User) SET u = row"})
+	if err != nil {
+		t.Fatalf("NewBulkWriter() error = %v", err)
+	}
+	if err := writer.Write(make(chan int)); !IsEncodingError(err) {
+		t.Errorf("BulkWriter.Write() of a row that cannot be encoded error = %v, want an Encoding error", err)
+	}
+	if err := writer.Write(nil); !IsTypeError(err) {
+		t.Errorf("BulkWriter.Write() of a nil row error = %v, want a Type error", err)
+	}
+	if _, err := writer.Close(); err != nil {
+		t.Fatalf("BulkWriter.Close() error = %v", err)
+	}
+	if err := writer.Write(bulkUser{}); !IsQueryError(err) {
+		t.Errorf("BulkWriter.Write() after Close error = %v, want a Query error", err)
+	}
+	if _, err := writer.Close(); !IsQueryError(err) {
+		t.Errorf("BulkWriter.Close() after Close error = %v, want a Query error", err)
+	}
+}
+
+type bulkBadUser struct {
+	Name    string   `neo4j:"name"`
+	Updates chan int `neo4j:"updates"`
+}
+
+func Test_bulkWriter_EncodingError(t *testing.T) {
+	m, driver := newFakeManager()
+
+	writer, err := NewBulkWriter(m, BulkWriterOptions{Query: "UNWIND $rows AS row CREATE (u:User) SET u = row"})
+	if err != nil {
+		t.Fatalf("NewBulkWriter() error = %v", err)
+	}
+
+	err = writer.Write(bulkUser{Name: "Alice"}, bulkBadUser{Name: "Bob", Updates: make(chan int)})
+	if !IsEncodingError(err) || !strings.Contains(err.Error(), "row 1") {
+		t.Errorf("BulkWriter.Write() error = %v, want an Encoding error on the row 1", err)
+	}
+	wantFailures := []EncodingFailure{{Path: "bulkBadUser.Updates", GotType: "chan int"}}
+	if failures := EncodingErrorFailures(err); !reflect.DeepEqual(failures, wantFailures) {
+		t.Errorf("EncodingErrorFailures() = %v, want %v", failures, wantFailures)
+	}
+
+	report, err := writer.Close()
+	if err != nil {
+		t.Fatalf("BulkWriter.Close() error = %v", err)
+	}
+	if report.RowsWritten != 0 || len(driver.sessions) != 0 {
+		t.Errorf("BulkWriter.Close() wrote %d rows, want none of the refused rows", report.RowsWritten)
+	}
+}
+
+func Test_bulkWriter_DoneContext(t *testing.T) {
+	m, driver := newFakeManager()
+	driver.runDelay = 50 * time.Millisecond
+
+	writer, err := NewBulkWriter(m, BulkWriterOptions{Query: "UNWIND $rows AS row CREATE (u:User) SET u = row", ChunkSize: 1})
+	if err != nil {
+		t.Fatalf("NewBulkWriter() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := writer.WriteContext(ctx, bulkUser{Name: "Alice"}); err != nil {
+		t.Fatalf("BulkWriter.WriteContext() error = %v", err)
+	}
+
+	report, err := writer.Close()
+	if !IsContextError(err) || report.RowsFailed != 1 {
+		t.Fatalf("BulkWriter.Close() = %+v, %v, want a Context error and 1 failed row", report, err)
+	}
+
+	// The aborted chunk releases its transaction as soon as its query returns
+	session := driver.sessions[0]
+	deadline := time.Now().Add(time.Second)
+	for !session.isClosed() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !session.isClosed() {
+		t.Errorf("BulkWriter.Close() did not close the session of the aborted chunk")
+	}
+	if len(m.Transactions()) != 0 {
+		t.Errorf("chunk transaction is still stored in the manager")
+	}
+}
+
+func Test_bulkWriter_DoneContextWhileWaiting(t *testing.T) {
+	m, driver := newFakeManager()
+	driver.runDelay = 50 * time.Millisecond
+
+	writer, err := NewBulkWriter(m, BulkWriterOptions{Query: "UNWIND $rows AS row CREATE (u:User) SET u = row", ChunkSize: 1})
+	if err != nil {
+		t.Fatalf("NewBulkWriter() error = %v", err)
+	}
+
+	// The only worker is busy with the first row, so the next chunks cannot be handed before the context expires
+	if err := writer.Write(bulkUser{Name: "Alice"}); err != nil {
+		t.Fatalf("BulkWriter.Write() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := writer.WriteContext(ctx, bulkUser{Name: "Alice"}, bulkUser{Name: "Alice"}); !IsContextError(err) {
+		t.Fatalf("BulkWriter.WriteContext() error = %v, want a Context error", err)
+	}
+
+	// Every row of the call was buffered, so they are all written by the next call
+	report, err := writer.Close()
+	if err != nil {
+		t.Fatalf("BulkWriter.Close() error = %v", err)
+	}
+	if report.RowsWritten != 3 || report.RowsFailed != 0 {
+		t.Errorf("BulkWriter.Close() rows written / failed = %d / %d, want 3 / 0", report.RowsWritten, report.RowsFailed)
+	}
+}
+
+func Test_bulkWriter_ConcurrentWrites(t *testing.T) {
+	m, driver := newFakeManager()
+	driver.runBlock = make(chan struct{})
+
+	writer, err := NewBulkWriter(m, BulkWriterOptions{Query: "UNWIND $rows AS row CREATE (u:User) SET u = row", ChunkSize: 2})
+	if err != nil {
+		t.Fatalf("NewBulkWriter() error = %v", err)
+	}
+	bulk := writer.(*bulkWriter)
+
+	// The first chunk blocks the only worker, then the second one waits for it
+	if err := writer.Write(bulkUser{Name: "Alice"}, bulkUser{Name: "Alice"}); err != nil {
+		t.Fatalf("BulkWriter.Write() error = %v", err)
+	}
+	waitingDone := make(chan Neo4GoError)
+	go func() {
+		waitingDone <- writer.Write(bulkUser{Name: "Alice"}, bulkUser{Name: "Alice"})
+	}()
+	for {
+		bulk.mutex.Lock()
+		isWaiting := bulk.chunkCount == 2 && len(bulk.pending) == 0
+		bulk.mutex.Unlock()
+		if isWaiting {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// A write that does not fill a chunk must not wait for the chunk of the other call
+	writeDone := make(chan Neo4GoError)
+	go func() {
+		writeDone <- writer.Write(bulkUser{Name: "Alice"})
+	}()
+	select {
+	case err := <-writeDone:
+		if err != nil {
+			t.Errorf("BulkWriter.Write() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("BulkWriter.Write() waited for the chunk of another call")
+	}
+
+	close(driver.runBlock)
+	if err := <-waitingDone; err != nil {
+		t.Errorf("BulkWriter.Write() error = %v", err)
+	}
+	report, err := writer.Close()
+	if err != nil || report.RowsWritten != 5 {
+		t.Errorf("BulkWriter.Close() = %+v, %v, want 5 rows written", report, err)
+	}
+}
@@ -0,0 +1,263 @@
+package neo4go
+
+import (
+	"strings"
+	"unicode"
+)
+
+// cypherTokenKind represents the kind of a token of a cypher query
+type cypherTokenKind uint
+
+const (
+	// A word in keyword position, such as MATCH or CREATE. Its text is upper case
+	cypherKeyword cypherTokenKind = iota
+
+	// A word that is not a keyword: variable, label, relationship type, property, map key, alias, function or procedure name
+	cypherName
+
+	// A string literal. Its text is the content of the string, without the quotes
+	cypherString
+
+	// A number literal
+	cypherNumber
+
+	// A parameter, either $name or the legacy {name}. Its text is the name of the parameter
+	cypherParameter
+
+	// Any other character, such as a parenthesis or an operator
+	cypherSymbol
+)
+
+// cypherToken represents a single token of a cypher query
+type cypherToken struct {
+	// The kind of the token
+	kind cypherTokenKind
+
+	// The text of the token
+	text string
+
+	// Tells if the token is a name written between backticks, which can never be a keyword
+	escaped bool
+}
+
+// cypherKeywords contains all the words that may be keywords of a query. Every other word is a name
+var cypherKeywords = map[string]bool{
+	"MATCH": true, "OPTIONAL": true, "WITH": true, "RETURN": true, "UNWIND": true, "WHERE": true, "ORDER": true,
+	"CALL": true, "YIELD": true, "UNION": true, "LOAD": true, "USE": true, "AS": true,
+	"EXPLAIN": true, "PROFILE": true, "CYPHER": true,
+	"CREATE": true, "MERGE": true, "DELETE": true, "DETACH": true, "SET": true, "REMOVE": true, "FOREACH": true,
+	"DROP": true, "ALTER": true, "RENAME": true, "GRANT": true, "REVOKE": true, "DENY": true,
+	"SHOW": true, "START": true, "STOP": true, "OR": true, "REPLACE": true, "COMPOSITE": true,
+	"INDEX": true, "CONSTRAINT": true, "UNIQUE": true, "FULLTEXT": true, "BTREE": true, "LOOKUP": true,
+	"TEXT": true, "POINT": true, "RANGE": true,
+	"DATABASE": true, "USER": true, "ROLE": true, "ALIAS": true,
+}
+
+// tokenizeCypher splits a cypher query into tokens. Whitespaces and comments are dropped,
+// and only the words in keyword position are returned as keywords
+func tokenizeCypher(query string) []cypherToken {
+	return resolveCypherWords(lexCypher(query))
+}
+
+// lexCypher splits a cypher query into raw tokens, in which every word is returned as a name
+func lexCypher(query string) []cypherToken {
+	runes := []rune(query)
+	tokens := make([]cypherToken, 0)
+
+	for i := 0; i < len(runes); {
+		current := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case unicode.IsSpace(current):
+			i++
+		case current == '/' && next == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case current == '/' && next == '*':
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				i++
+			}
+			i += 2
+		case current == '\'' || current == '"':
+			text, length := lexCypherString(runes[i:])
+			tokens = append(tokens, cypherToken{kind: cypherString, text: text})
+			i += length
+		case current == '`':
+			text, length := lexCypherEscapedName(runes[i:])
+			tokens = append(tokens, cypherToken{kind: cypherName, text: text, escaped: true})
+			i += length
+		case current == '$':
+			name, length := lexCypherParameterName(runes[i+1:])
+			if length == 0 {
+				tokens = append(tokens, cypherToken{kind: cypherSymbol, text: "$"})
+				i++
+			} else {
+				tokens = append(tokens, cypherToken{kind: cypherParameter, text: name})
+				i += 1 + length
+			}
+		case unicode.IsDigit(current):
+			start := i
+			for i < len(runes) && (isCypherWordRune(runes[i]) || (runes[i] == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]))) {
+				i++
+			}
+			tokens = append(tokens, cypherToken{kind: cypherNumber, text: string(runes[start:i])})
+		case isCypherWordRune(current):
+			start := i
+			for i < len(runes) && isCypherWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, cypherToken{kind: cypherName, text: string(runes[start:i])})
+		default:
+			tokens = append(tokens, cypherToken{kind: cypherSymbol, text: string(current)})
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// isCypherWordRune tells if a character can be part of an unescaped word
+func isCypherWordRune(char rune) bool {
+	return char == '_' || unicode.IsLetter(char) || unicode.IsDigit(char)
+}
+
+// lexCypherString reads a string literal starting with its opening quote.
+// It returns the content of the string and the number of characters read
+func lexCypherString(runes []rune) (string, int) {
+	quote := runes[0]
+	var builder strings.Builder
+
+	for i := 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				builder.WriteRune(runes[i])
+			}
+		case quote:
+			return builder.String(), i + 1
+		default:
+			builder.WriteRune(runes[i])
+		}
+	}
+
+	// An unterminated string extends to the end of the query
+	return builder.String(), len(runes)
+}
+
+// lexCypherEscapedName reads a backticked name starting with its opening backtick.
+// It returns the name and the number of characters read
+func lexCypherEscapedName(runes []rune) (string, int) {
+	var builder strings.Builder
+
+	for i := 1; i < len(runes); i++ {
+		if runes[i] != '`' {
+			builder.WriteRune(runes[i])
+			continue
+		}
+
+		// A doubled backtick is an escaped backtick
+		if i+1 < len(runes) && runes[i+1] == '`' {
+			builder.WriteRune('`')
+			i++
+			continue
+		}
+
+		return builder.String(), i + 1
+	}
+
+	return builder.String(), len(runes)
+}
+
+// lexCypherParameterName reads the name of a parameter following its $ sign.
+// It returns the name and the number of characters read, which is 0 if there is no valid name
+func lexCypherParameterName(runes []rune) (string, int) {
+	if len(runes) > 0 && runes[0] == '`' {
+		return lexCypherEscapedName(runes)
+	}
+
+	length := 0
+	for length < len(runes) && isCypherWordRune(runes[length]) {
+		length++
+	}
+
+	return string(runes[:length]), length
+}
+
+// resolveCypherWords turns the names that are in keyword position into keywords, and the legacy {name} parameters into parameters
+func resolveCypherWords(rawTokens []cypherToken) []cypherToken {
+	tokens := make([]cypherToken, 0, len(rawTokens))
+
+	// Tells if the previous names are a chain of labels or relationship types, such as :A|B or :A:B
+	inLabelChain := false
+
+	for i := 0; i < len(rawTokens); i++ {
+		token := rawTokens[i]
+		var previous, next *cypherToken
+		if len(tokens) > 0 {
+			previous = &tokens[len(tokens)-1]
+		}
+		if i+1 < len(rawTokens) {
+			next = &rawTokens[i+1]
+		}
+
+		// Legacy parameters are a single name between braces, which is not a map projection such as n{prop}
+		if isCypherSymbol(&token, "{") && i+2 < len(rawTokens) &&
+			(rawTokens[i+1].kind == cypherName || rawTokens[i+1].kind == cypherNumber) && isCypherSymbol(&rawTokens[i+2], "}") &&
+			(previous == nil || (previous.kind != cypherName && !isCypherSymbol(previous, ")"))) {
+			tokens = append(tokens, cypherToken{kind: cypherParameter, text: rawTokens[i+1].text})
+			i += 2
+			inLabelChain = false
+			continue
+		}
+
+		if token.kind != cypherName {
+			tokens = append(tokens, token)
+			if !isCypherSymbol(&token, "|") && !isCypherSymbol(&token, ":") {
+				inLabelChain = false
+			}
+			continue
+		}
+
+		isLabel := isCypherSymbol(previous, ":") || (inLabelChain && isCypherSymbol(previous, "|"))
+		inLabelChain = isLabel
+
+		if !isLabel && !token.escaped && isCypherKeywordPosition(previous, next) && cypherKeywords[strings.ToUpper(token.text)] {
+			token = cypherToken{kind: cypherKeyword, text: strings.ToUpper(token.text)}
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens
+}
+
+// isCypherKeywordPosition tells if a word between the given tokens may be a keyword.
+// Property names, map keys, aliases, variables in parentheses or in a list of expressions, operands and words ending the query never are
+func isCypherKeywordPosition(previous *cypherToken, next *cypherToken) bool {
+	if isCypherSymbol(previous, ".") || isCypherSymbol(previous, "(") || isCypherSymbol(previous, "$") {
+		return false
+	}
+	if previous != nil && previous.kind == cypherSymbol && strings.Contains(",=+-/%^<>[", previous.text) {
+		return false
+	}
+	if previous != nil && previous.kind == cypherKeyword && previous.text == "AS" {
+		return false
+	}
+	if next == nil || (next.kind == cypherName && !next.escaped && strings.ToUpper(next.text) == "AS") {
+		return false
+	}
+
+	return !(isCypherSymbol(next, ":") || isCypherSymbol(next, ".") || isCypherSymbol(next, ")") ||
+		isCypherSymbol(next, ",") || isCypherSymbol(next, "="))
+}
+
+// isCypherSymbol tells if the token is the given symbol
+func isCypherSymbol(token *cypherToken, symbol string) bool {
+	return token != nil && token.kind == cypherSymbol && token.text == symbol
+}
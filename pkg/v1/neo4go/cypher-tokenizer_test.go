@@ -0,0 +1,135 @@
+package neo4go
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_tokenizeCypher(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []cypherToken
+	}{
+		{
+			name:  "Should upper case keywords and keep names",
+			query: "match (n:User) return n",
+			want: []cypherToken{
+				{kind: cypherKeyword, text: "MATCH"},
+				{kind: cypherSymbol, text: "("},
+				{kind: cypherName, text: "n"},
+				{kind: cypherSymbol, text: ":"},
+				{kind: cypherName, text: "User"},
+				{kind: cypherSymbol, text: ")"},
+				{kind: cypherKeyword, text: "RETURN"},
+				{kind: cypherName, text: "n"},
+			},
+		},
+		{
+			name:  "Should drop comments",
+			query: "// CREATE\nRETURN /* SET */ 1",
+			want: []cypherToken{
+				{kind: cypherKeyword, text: "RETURN"},
+				{kind: cypherNumber, text: "1"},
+			},
+		},
+		{
+			name:  "Should read string literals with escaped quotes",
+			query: `RETURN 'it\'s set', "create"`,
+			want: []cypherToken{
+				{kind: cypherKeyword, text: "RETURN"},
+				{kind: cypherString, text: "it's set"},
+				{kind: cypherSymbol, text: ","},
+				{kind: cypherString, text: "create"},
+			},
+		},
+		{
+			name:  "Should never turn escaped names into keywords",
+			query: "RETURN 1 AS `CREATE`, n.`a``b`",
+			want: []cypherToken{
+				{kind: cypherKeyword, text: "RETURN"},
+				{kind: cypherNumber, text: "1"},
+				{kind: cypherKeyword, text: "AS"},
+				{kind: cypherName, text: "CREATE", escaped: true},
+				{kind: cypherSymbol, text: ","},
+				{kind: cypherName, text: "n"},
+				{kind: cypherSymbol, text: "."},
+				{kind: cypherName, text: "a`b", escaped: true},
+			},
+		},
+		{
+			name:  "Should keep properties, labels, relationship types and map keys as names",
+			query: "MATCH ()-[:SET|DELETE]->(n:Create) RETURN n.set, {remove: 1}",
+			want: []cypherToken{
+				{kind: cypherKeyword, text: "MATCH"},
+				{kind: cypherSymbol, text: "("},
+				{kind: cypherSymbol, text: ")"},
+				{kind: cypherSymbol, text: "-"},
+				{kind: cypherSymbol, text: "["},
+				{kind: cypherSymbol, text: ":"},
+				{kind: cypherName, text: "SET"},
+				{kind: cypherSymbol, text: "|"},
+				{kind: cypherName, text: "DELETE"},
+				{kind: cypherSymbol, text: "]"},
+				{kind: cypherSymbol, text: "-"},
+				{kind: cypherSymbol, text: ">"},
+				{kind: cypherSymbol, text: "("},
+				{kind: cypherName, text: "n"},
+				{kind: cypherSymbol, text: ":"},
+				{kind: cypherName, text: "Create"},
+				{kind: cypherSymbol, text: ")"},
+				{kind: cypherKeyword, text: "RETURN"},
+				{kind: cypherName, text: "n"},
+				{kind: cypherSymbol, text: "."},
+				{kind: cypherName, text: "set"},
+				{kind: cypherSymbol, text: ","},
+				{kind: cypherSymbol, text: "{"},
+				{kind: cypherName, text: "remove"},
+				{kind: cypherSymbol, text: ":"},
+				{kind: cypherNumber, text: "1"},
+				{kind: cypherSymbol, text: "}"},
+			},
+		},
+		{
+			name:  "Should read parameters",
+			query: "RETURN $name, $`odd name`, $0, {legacy}, n{prop}",
+			want: []cypherToken{
+				{kind: cypherKeyword, text: "RETURN"},
+				{kind: cypherParameter, text: "name"},
+				{kind: cypherSymbol, text: ","},
+				{kind: cypherParameter, text: "odd name"},
+				{kind: cypherSymbol, text: ","},
+				{kind: cypherParameter, text: "0"},
+				{kind: cypherSymbol, text: ","},
+				{kind: cypherParameter, text: "legacy"},
+				{kind: cypherSymbol, text: ","},
+				{kind: cypherName, text: "n"},
+				{kind: cypherSymbol, text: "{"},
+				{kind: cypherName, text: "prop"},
+				{kind: cypherSymbol, text: "}"},
+			},
+		},
+		{
+			name:  "Should read decimal numbers but not ranges",
+			query: "RETURN 1.5, [1..3]",
+			want: []cypherToken{
+				{kind: cypherKeyword, text: "RETURN"},
+				{kind: cypherNumber, text: "1.5"},
+				{kind: cypherSymbol, text: ","},
+				{kind: cypherSymbol, text: "["},
+				{kind: cypherNumber, text: "1"},
+				{kind: cypherSymbol, text: "."},
+				{kind: cypherSymbol, text: "."},
+				{kind: cypherNumber, text: "3"},
+				{kind: cypherSymbol, text: "]"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokenizeCypher(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenizeCypher() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package neo4go
 
 import (
+	"encoding"
 	"fmt"
 	"reflect"
 
@@ -26,6 +27,13 @@ type Decoder interface {
 	DecodeRecordMap(RecordMap, interface{}) Neo4GoError
 }
 
+// Neo4jUnmarshaler is the interface implemented by the types that decode themselves from query results.
+// The decoder calls UnmarshalNeo4j on a pointer to the field instead of decoding the value into it
+type Neo4jUnmarshaler interface {
+	// UnmarshalNeo4j sets the value from the raw value returned by the neo4j-go-driver
+	UnmarshalNeo4j(interface{}) error
+}
+
 // neo4goDecoder is the default implementation of the Decoder interface
 type neo4goDecoder struct {
 	// The options used when decoding an object
@@ -45,6 +53,17 @@ func NewDecoder(options *mapstructure.DecoderConfig) Decoder {
 		usedOpt.TagName = internalMain.DefaultDecodingTagName
 	}
 
+	// Decode the embedded structs from the map of their parent, like the encoder flattens them.
+	// The other fields are squashed by mapstructure only when they have the squash option in their tag
+	usedOpt.Squash = true
+
+	// Let the unmarshalers decode themselves, and allocate the squashed pointers so that mapstructure can decode them
+	decodeHooks := []mapstructure.DecodeHookFunc{unmarshalerDecodeHook, squashDecodeHook(usedOpt.TagName)}
+	if usedOpt.DecodeHook != nil {
+		decodeHooks = append(decodeHooks, usedOpt.DecodeHook)
+	}
+	usedOpt.DecodeHook = mapstructure.ComposeDecodeHookFunc(decodeHooks...)
+
 	// Instanciate and return the decoder
 	newNeo4GoDecoder := neo4goDecoder{
 		options: usedOpt,
@@ -402,3 +421,95 @@ func (decoder *neo4goDecoder) DecodePath(path interface{}, outputNodes interface
 func (decoder *neo4goDecoder) DecodeRecordMap(rec RecordMap, output interface{}) Neo4GoError {
 	return decoder.decodeSingleValue(rec.RawMap(), output)
 }
+
+// squashDecodeHook returns a decode hook that allocates the nil pointers of the squashed fields of a decoded struct.
+// Mapstructure squashes them into the struct so that they are decoded from the map of their parent, but cannot squash a nil pointer
+func squashDecodeHook(tagName string) mapstructure.DecodeHookFuncValue {
+	return func(from reflect.Value, to reflect.Value) (interface{}, error) {
+		if !from.IsValid() {
+			return nil, nil
+		}
+
+		allocateSquashedPointers(tagName, to, make(map[reflect.Type]bool))
+
+		return from.Interface(), nil
+	}
+}
+
+// allocateSquashedPointers sets a new struct to the nil pointers of the squashed fields of a struct value, and of the squashed fields within them.
+// The visited types are the struct types being allocated, so that a type squashing a pointer to itself does not allocate it forever.
+// The pointers to unexported structs cannot be set, so they are only decoded when they are allocated beforehand
+func allocateSquashedPointers(tagName string, structVal reflect.Value, visited map[reflect.Type]bool) {
+	if structVal.Kind() != reflect.Struct || visited[structVal.Type()] {
+		return
+	}
+	visited[structVal.Type()] = true
+	defer delete(visited, structVal.Type())
+
+	for _, fieldPlan := range getEncodingPlan(tagName, structVal.Type()).fields {
+		if !fieldPlan.squash {
+			continue
+		}
+
+		fieldVal := structVal.Field(fieldPlan.index)
+		if fieldVal.Kind() == reflect.Ptr && fieldVal.IsNil() {
+			if !fieldVal.CanSet() || visited[fieldVal.Type().Elem()] {
+				continue
+			}
+			fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
+		}
+
+		allocateSquashedPointers(tagName, reflect.Indirect(fieldVal), visited)
+	}
+}
+
+// unmarshalerDecodeHook is the decode hook that calls UnmarshalNeo4j on the values implementing Neo4jUnmarshaler.
+// It then gives the unmarshaled value to mapstructure, which sets it as it is
+func unmarshalerDecodeHook(from reflect.Value, to reflect.Value) (interface{}, error) {
+	if !from.IsValid() {
+		return nil, nil
+	}
+
+	unmarshaler, isUnmarshaler := addressableAs(to, from.Type()).(Neo4jUnmarshaler)
+	if !isUnmarshaler {
+		return from.Interface(), nil
+	}
+
+	if err := unmarshaler.UnmarshalNeo4j(from.Interface()); err != nil {
+		return nil, err
+	}
+
+	return to.Interface(), nil
+}
+
+// TextUnmarshalerDecodeHook returns a decode hook that decodes the strings into the values implementing encoding.TextUnmarshaler.
+// It is the counterpart of EncoderOptions.UseTextMarshaler, to be given to the DecodeHook of the decoder options
+func TextUnmarshalerDecodeHook() mapstructure.DecodeHookFuncValue {
+	return func(from reflect.Value, to reflect.Value) (interface{}, error) {
+		if !from.IsValid() {
+			return nil, nil
+		}
+
+		text, isString := from.Interface().(string)
+		unmarshaler, isUnmarshaler := addressableAs(to, from.Type()).(encoding.TextUnmarshaler)
+		if !isString || !isUnmarshaler {
+			return from.Interface(), nil
+		}
+
+		if err := unmarshaler.UnmarshalText([]byte(text)); err != nil {
+			return nil, err
+		}
+
+		return to.Interface(), nil
+	}
+}
+
+// addressableAs returns a pointer to the decoded value as an interface, so that its methods with pointer receivers can be found.
+// It returns nil if the value cannot be addressed, or if it is already decoded from a value of its own type
+func addressableAs(to reflect.Value, fromType reflect.Type) interface{} {
+	if !to.CanAddr() || to.Type() == fromType || !to.Addr().CanInterface() {
+		return nil
+	}
+
+	return to.Addr().Interface()
+}
@@ -0,0 +1,199 @@
+package neo4go
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// testNode is a minimal implementation of neo4j.Node holding the given properties
+type testNode struct {
+	props map[string]interface{}
+}
+
+func (n *testNode) Id() int64                     { return 1 }
+func (n *testNode) Labels() []string              { return []string{"Entity"} }
+func (n *testNode) Props() map[string]interface{} { return n.props }
+
+func Test_neo4goDecoder_Inline(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *mapstructure.DecoderConfig
+		props   map[string]interface{}
+		want    auditedEntity
+	}{
+		{
+			name: "Should decode the embedded structs and the squashed fields from the properties of their parent",
+			props: map[string]interface{}{
+				"id":        "1",
+				"version":   int64(2),
+				"createdBy": "admin",
+				"name":      "Alice",
+				"owner":     map[string]interface{}{"id": "0", "version": int64(1)},
+			},
+			want: auditedEntity{
+				BaseEntity: BaseEntity{ID: "1", Version: 2},
+				Audit:      entityAudit{CreatedBy: "admin", Version: 2},
+				Owner:      &BaseEntity{ID: "0", Version: 1},
+				Name:       "Alice",
+			},
+		},
+		{
+			name:    "Should use every property of the squashed fields when the unused properties are refused",
+			options: &mapstructure.DecoderConfig{ErrorUnused: true},
+			props: map[string]interface{}{
+				"id":        "1",
+				"version":   int64(2),
+				"createdBy": "admin",
+				"name":      "Alice",
+			},
+			want: auditedEntity{
+				BaseEntity: BaseEntity{ID: "1", Version: 2},
+				Audit:      entityAudit{CreatedBy: "admin", Version: 2},
+				Name:       "Alice",
+			},
+		},
+
+		{
+			name: "Should still call the custom decode hook",
+			options: &mapstructure.DecoderConfig{
+				DecodeHook: func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+					if to.Kind() == reflect.String {
+						return "hooked", nil
+					}
+					return data, nil
+				},
+			},
+			props: map[string]interface{}{"id": "1", "name": "Alice"},
+			want: auditedEntity{
+				BaseEntity: BaseEntity{ID: "hooked"},
+				Name:       "hooked",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := auditedEntity{}
+			if err := NewDecoder(tt.options).DecodeNode(&testNode{props: tt.props}, &got); err != nil {
+				t.Fatalf("neo4goDecoder.DecodeNode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("neo4goDecoder.DecodeNode() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+type pointedEntity struct {
+	*BaseEntity
+	Audit *entityAudit `neo4j:",squash"`
+}
+
+func Test_neo4goDecoder_SquashedPointers(t *testing.T) {
+	props := map[string]interface{}{"id": "1", "version": int64(2), "createdBy": "admin"}
+
+	got := pointedEntity{}
+	if err := NewDecoder(&mapstructure.DecoderConfig{ErrorUnused: true}).DecodeNode(&testNode{props: props}, &got); err != nil {
+		t.Fatalf("neo4goDecoder.DecodeNode() error = %v", err)
+	}
+	want := pointedEntity{
+		BaseEntity: &BaseEntity{ID: "1", Version: 2},
+		Audit:      &entityAudit{CreatedBy: "admin", Version: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("neo4goDecoder.DecodeNode() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_neo4goDecoder_UnexportedEmbedded(t *testing.T) {
+	props := map[string]interface{}{"key": "k1", "name": "Alice", "createdBy": "admin", "version": int64(2)}
+
+	tests := []struct {
+		name string
+		got  keyedEntity
+		want keyedEntity
+	}{
+		{
+			name: "Should decode the unexported embedded structs, but not allocate their pointers",
+			want: keyedEntity{entityKey: entityKey{Key: "k1"}, Name: "Alice"},
+		},
+		{
+			name: "Should decode the unexported embedded pointers allocated beforehand",
+			got:  keyedEntity{entityAudit: &entityAudit{}},
+			want: keyedEntity{entityKey: entityKey{Key: "k1"}, entityAudit: &entityAudit{CreatedBy: "admin", Version: 2}, Name: "Alice"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewDecoder(nil).DecodeNode(&testNode{props: props}, &tt.got); err != nil {
+				t.Fatalf("neo4goDecoder.DecodeNode() error = %v", err)
+			}
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("neo4goDecoder.DecodeNode() = %+v, want %+v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+// ChainedEntity embeds a pointer to its own type, which must not be allocated endlessly
+type ChainedEntity struct {
+	*ChainedEntity
+	Name string `neo4j:"name"`
+}
+
+func Test_neo4goDecoder_SelfSquashedPointer(t *testing.T) {
+	got := ChainedEntity{}
+	if err := NewDecoder(nil).DecodeNode(&testNode{props: map[string]interface{}{"name": "Alice"}}, &got); err != nil {
+		t.Fatalf("neo4goDecoder.DecodeNode() error = %v", err)
+	}
+	if want := (ChainedEntity{Name: "Alice"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("neo4goDecoder.DecodeNode() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_neo4goDecoder_Unmarshalers(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *mapstructure.DecoderConfig
+		props   map[string]interface{}
+		want    marshaledAccount
+		wantErr bool
+	}{
+		{
+			name:  "Should decode the values with their unmarshalers",
+			props: map[string]interface{}{"balance": "12 EUR", "level": "++"},
+			want:  marshaledAccount{Balance: 12, Level: &testLevel{value: 2}},
+		},
+		{
+			name:    "Should return the error of an unmarshaler",
+			props:   map[string]interface{}{"balance": int64(12)},
+			wantErr: true,
+		},
+		{
+			name:    "Should decode the text unmarshalers with their decode hook",
+			options: &mapstructure.DecoderConfig{DecodeHook: TextUnmarshalerDecodeHook()},
+			props:   map[string]interface{}{"ip": "10.0.0.1"},
+			want:    marshaledAccount{IP: net.ParseIP("10.0.0.1")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := marshaledAccount{}
+			err := NewDecoder(tt.options).DecodeNode(&testNode{props: tt.props}, &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("neo4goDecoder.DecodeNode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !IsDecodingError(err) {
+					t.Errorf("neo4goDecoder.DecodeNode() error = %v, want a Decoding error", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("neo4goDecoder.DecodeNode() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package neo4go
+
+import (
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// Driver represents the connection pool used by a manager to open sessions.
+// It is implemented by default over the neo4j-go-driver, but any implementation can be given to NewManagerWithDriver
+type Driver interface {
+	// NewSession creates a new session based on the specified session configuration
+	NewSession(neo4j.SessionConfig) (Session, error)
+
+	// VerifyConnectivity returns nil if the driver can connect to the database
+	VerifyConnectivity() error
+
+	// Close closes the driver and all its underlying connections
+	Close() error
+}
+
+// Session represents a logical connection to the database, used by a manager to run queries and transactions
+type Session interface {
+	// LastBookmark returns the bookmark received following the last successfully completed transaction
+	LastBookmark() string
+
+	// BeginTransaction starts a new explicit transaction on this session
+	BeginTransaction(...func(*neo4j.TransactionConfig)) (Transaction, error)
+
+	// Run executes an auto-commit query and returns its result
+	Run(string, map[string]interface{}, ...func(*neo4j.TransactionConfig)) (neo4j.Result, error)
+
+	// Close closes any open resources and marks this session as unusable
+	Close() error
+}
+
+// Transaction represents an explicit transaction opened by a session
+type Transaction interface {
+	// Run executes a query in this transaction and returns its result
+	Run(string, map[string]interface{}) (neo4j.Result, error)
+
+	// Commit commits the transaction
+	Commit() error
+
+	// Rollback rolls back the transaction
+	Rollback() error
+
+	// Close rolls back the transaction if it is not already committed or rolled back, and releases its resources
+	Close() error
+}
+
+// neo4jDriver is the implementation of the Driver interface over the neo4j-go-driver
+type neo4jDriver struct {
+	driver neo4j.Driver
+}
+
+// newNeo4jDriver creates a new neo4j-go-driver driver from the manager config
+func newNeo4jDriver(options ManagerOptions) (Driver, Neo4GoError) {
+	// Select the right auth protocol
+	usedAuth := neo4j.NoAuth()
+	if options.CustomAuth != nil {
+		usedAuth = *options.CustomAuth
+	} else if options.Username != "" && options.Password != "" {
+		usedAuth = neo4j.BasicAuth(options.Username, options.Password, options.Realm)
+	}
+
+	// Create the driver
+	newDriver, err := neo4j.NewDriver(
+		options.URI,
+		usedAuth,
+		options.Configurers...,
+	)
+	if err != nil {
+		return nil, toDriverError(err)
+	}
+
+	return &neo4jDriver{driver: newDriver}, nil
+}
+
+// NewSession creates a new session based on the specified session configuration
+func (d *neo4jDriver) NewSession(config neo4j.SessionConfig) (Session, error) {
+	session, err := d.driver.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &neo4jSession{session: session}, nil
+}
+
+// VerifyConnectivity returns nil if the driver can connect to the database
+func (d *neo4jDriver) VerifyConnectivity() error {
+	return d.driver.VerifyConnectivity()
+}
+
+// Close closes the driver and all its underlying connections
+func (d *neo4jDriver) Close() error {
+	return d.driver.Close()
+}
+
+// neo4jSession is the implementation of the Session interface over the neo4j-go-driver
+type neo4jSession struct {
+	session neo4j.Session
+}
+
+// LastBookmark returns the bookmark received following the last successfully completed transaction
+func (s *neo4jSession) LastBookmark() string {
+	return s.session.LastBookmark()
+}
+
+// BeginTransaction starts a new explicit transaction on this session
+func (s *neo4jSession) BeginTransaction(configurers ...func(*neo4j.TransactionConfig)) (Transaction, error) {
+	tx, err := s.session.BeginTransaction(configurers...)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// Run executes an auto-commit query and returns its result
+func (s *neo4jSession) Run(query string, params map[string]interface{}, configurers ...func(*neo4j.TransactionConfig)) (neo4j.Result, error) {
+	return s.session.Run(query, params, configurers...)
+}
+
+// Close closes any open resources and marks this session as unusable
+func (s *neo4jSession) Close() error {
+	return s.session.Close()
+}
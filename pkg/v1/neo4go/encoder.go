@@ -1,21 +1,46 @@
 package neo4go
 
 import (
+	"encoding"
 	"fmt"
 	"log"
 	"reflect"
 	"strconv"
-	"strings"
 	"time"
 
+	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
 	internalMain "github.com/UlysseGuyon/neo4go/internal/neo4go"
 	"github.com/neo4j/neo4j-go-driver/neo4j"
 )
 
 // Encoder allows a user to encode any type of data (including custom structs) into neo4go query input values
 type Encoder interface {
-	// Encode takes any object and encodes it into an object accepted by the neo4go query system
+	// Encode takes any object and encodes it into an object accepted by the neo4go query system.
+	// The values that cannot be encoded are set to nil, unless the encoder is strict
 	Encode(interface{}) InputStruct
+
+	// EncodeWithError is the same as Encode, but returns an Encoding error listing the path and type of every value that could not be encoded.
+	// The encoded object is still returned with these values set to nil, unless the encoder is strict
+	EncodeWithError(interface{}) (InputStruct, Neo4GoError)
+}
+
+// EncodingFailure represents a value that an encoder could not encode
+type EncodingFailure struct {
+	// The path of the value in the encoded object, as in User.Address.Geo or Tags[3]
+	Path string
+
+	// The Go type of the value
+	GotType string
+
+	// The error returned by the marshaler of the value, if it has one
+	Err string
+}
+
+// Neo4jMarshaler is the interface implemented by the types that encode themselves into query inputs.
+// The encoder calls MarshalNeo4j instead of encoding their fields or underlying value
+type Neo4jMarshaler interface {
+	// MarshalNeo4j returns the query input representing the value
+	MarshalNeo4j() (InputStruct, error)
 }
 
 // EncodeHookFunc represents a function that converts a specific type of value into a neo4go query input
@@ -31,12 +56,52 @@ type EncoderOptions struct {
 
 	// Tells if the encoder should be silent or not when it finds an object it cannot decode
 	Silent bool
+
+	// Tells if the encoder should reject a whole object when any of its values cannot be encoded, instead of setting these values to nil
+	Strict bool
+
+	// Tells if the values implementing encoding.TextMarshaler are encoded as strings, unless they are primitives, times or points.
+	// It is useful for types such as net.IP or UUIDs, which would be encoded as arrays of bytes otherwise
+	UseTextMarshaler bool
 }
 
 // neo4goEncoder is the default implementation of the Encoder interface
 type neo4goEncoder struct {
 	// The configuration of this encoder
 	options EncoderOptions
+
+	// The hook called first for every value, which detects nil values then calls the custom hook of the options
+	hook EncodeHookFunc
+
+	// The hook that encodes the primitive values, called after the marshalers
+	defaultHook EncodeHookFunc
+
+	// Tells if a custom hook was given to this encoder, so that every value must go through the hooks
+	hasCustomHook bool
+}
+
+// encodingState keeps track of the values that could not be encoded during a single encoding
+type encodingState struct {
+	// Every value that could not be encoded so far
+	failures []internalErr.EncodingFailure
+}
+
+// fail records that the value at the given path could not be encoded
+func (state *encodingState) fail(path string, v reflect.Value) {
+	state.failWithError(path, v, nil)
+}
+
+// failWithError records that the marshaler of the value at the given path returned an error
+func (state *encodingState) failWithError(path string, v reflect.Value, err error) {
+	failure := internalErr.EncodingFailure{Path: path, GotType: "nil"}
+	if v.IsValid() {
+		failure.GotType = v.Type().String()
+	}
+	if err != nil {
+		failure.Err = err.Error()
+	}
+
+	state.failures = append(state.failures, failure)
 }
 
 // NewEncoder creates a new instance of Encoder, with a given config. A nil config will result in the default config beinng applied
@@ -48,7 +113,8 @@ func NewEncoder(opt *EncoderOptions) Encoder {
 	}
 
 	newEncoder := neo4goEncoder{
-		options: usedOpt,
+		options:       usedOpt,
+		hasCustomHook: usedOpt.EncodeHook != nil,
 	}
 
 	// Use the default encoding tag name if none is given
@@ -56,34 +122,173 @@ func NewEncoder(opt *EncoderOptions) Encoder {
 		newEncoder.options.TagName = internalMain.DefaultEncodingTagName
 	}
 
-	// Set the encoding hook as first the nil detector, then the custom hooks, then the default hooks
-	// As the ComposeEncodeHookFunc begins the calls by the beggining of the hook list and stops at the first that succeeds
-	newEncoder.options.EncodeHook = ComposeEncodeHookFunc(
+	// Set the encoding hook as first the nil detector, then the custom hooks
+	// As the ComposeEncodeHookFunc begins the calls by the beggining of the hook list and stops at the first that succeeds.
+	// The marshalers, then the default hooks, are only used for the values that these hooks did not encode.
+	// The structs, arrays and maps that no hook encoded are then encoded by the encoder itself, value by value
+	newEncoder.hook = ComposeEncodeHookFunc(
 		defaultHookNil,                // NOTE This one must be first in order to detect nil values without panic
 		newEncoder.options.EncodeHook, // NOTE This one must be before the default hooks so that it won't be overriden
-		newEncoder.getDefaultHook(),
 	)
+	newEncoder.defaultHook = newEncoder.getDefaultHook()
 
 	return &newEncoder
 }
 
-// Encode takes any object and encodes it into an object accepted by the neo4go query system
+// Encode takes any object and encodes it into an object accepted by the neo4go query system.
+// The values that cannot be encoded are set to nil, unless the encoder is strict
 func (encoder *neo4goEncoder) Encode(obj interface{}) InputStruct {
+	encodedObj, err := encoder.EncodeWithError(obj)
+	if err != nil && !encoder.options.Silent {
+		log.Println(err.FmtError())
+	}
+
+	return encodedObj
+}
+
+// EncodeWithError is the same as Encode, but returns an Encoding error listing the path and type of every value that could not be encoded.
+// The encoded object is still returned with these values set to nil, unless the encoder is strict
+func (encoder *neo4goEncoder) EncodeWithError(obj interface{}) (InputStruct, Neo4GoError) {
 	objValue := reflect.ValueOf(obj)
 
-	// Call the hook of the encoder and log if it could not encode the object
-	if encodedObj, canEncode := encoder.options.EncodeHook(objValue, obj); canEncode {
+	state := encodingState{}
+	encodedObj := encoder.encodeValue(objValue, obj, encodingRootPath(objValue), &state)
+	if len(state.failures) == 0 {
+		return encodedObj, nil
+	}
+
+	err := &internalErr.EncodingError{
+		Err:      "Could not encode every value of the object",
+		Failures: state.failures,
+	}
+	if encoder.options.Strict {
+		return nil, err
+	}
+
+	return encodedObj, err
+}
+
+// encodeValue encodes a value found at the given path, first with the hooks and the marshalers, then as a struct, an array or a map.
+// The values that cannot be encoded are recorded in the state and encoded as nil
+func (encoder *neo4goEncoder) encodeValue(v reflect.Value, i interface{}, path string, state *encodingState) InputStruct {
+	if encodedObj, canEncode := encoder.hook(v, i); canEncode {
 		return encodedObj
 	}
 
-	if !encoder.options.Silent {
-		log.Printf("Could not encode object : (Type : %s) %+v\n", objValue.Type().String(), obj)
+	if marshaler, isMarshaler := asNeo4jMarshaler(v, i); isMarshaler {
+		encodedObj, err := marshaler.MarshalNeo4j()
+		if err != nil {
+			state.failWithError(path, v, err)
+			return nil
+		}
+
+		return encodedObj
+	}
+
+	if encodedObj, canEncode := encoder.defaultHook(v, i); canEncode {
+		return encodedObj
+	}
+
+	if encoder.options.UseTextMarshaler {
+		if marshaler, isMarshaler := asTextMarshaler(v, i); isMarshaler {
+			text, err := marshaler.MarshalText()
+			if err != nil {
+				state.failWithError(path, v, err)
+				return nil
+			}
+
+			textStr := string(text)
+			return NewInputString(&textStr)
+		}
 	}
 
+	usedVal := GetValueElem(v)
+	switch usedVal.Kind() {
+	case reflect.Struct:
+		return encoder.encodeStruct(usedVal, path, state)
+	case reflect.Array, reflect.Slice:
+		return encoder.encodeArray(usedVal, path, state)
+	case reflect.Map:
+		return encoder.encodeMap(usedVal, path, state)
+	}
+
+	state.fail(path, v)
+
 	return nil
 }
 
-// getDefaultHook returns a composition of all the default encode hook functions used for primitive values and array/map/struct
+// encodeNested encodes a field, an item or a map value of an encoded object. The values that are not exported are recorded as failures
+func (encoder *neo4goEncoder) encodeNested(v reflect.Value, path string, state *encodingState) InputStruct {
+	if !v.CanInterface() {
+		state.fail(path, v)
+		return nil
+	}
+
+	// Encode the value as if it was given on its own, so that the hooks get its concrete type rather than an interface.
+	// Other values are kept as they are, so that the marshalers with pointer receivers are found on addressable values
+	i := v.Interface()
+	if v.Kind() == reflect.Interface {
+		v = reflect.ValueOf(i)
+	}
+
+	return encoder.encodeValue(v, i, path, state)
+}
+
+// asNeo4jMarshaler returns the value as a Neo4jMarshaler, looking for the method on its pointer too when it is addressable
+func asNeo4jMarshaler(v reflect.Value, i interface{}) (Neo4jMarshaler, bool) {
+	if marshaler, isMarshaler := i.(Neo4jMarshaler); isMarshaler {
+		return marshaler, true
+	}
+	if v.CanAddr() && v.Addr().CanInterface() {
+		marshaler, isMarshaler := v.Addr().Interface().(Neo4jMarshaler)
+		return marshaler, isMarshaler
+	}
+
+	return nil, false
+}
+
+// asTextMarshaler returns the value as an encoding.TextMarshaler, looking for the method on its pointer too when it is addressable
+func asTextMarshaler(v reflect.Value, i interface{}) (encoding.TextMarshaler, bool) {
+	if marshaler, isMarshaler := i.(encoding.TextMarshaler); isMarshaler {
+		return marshaler, true
+	}
+	if v.CanAddr() && v.Addr().CanInterface() {
+		marshaler, isMarshaler := v.Addr().Interface().(encoding.TextMarshaler)
+		return marshaler, isMarshaler
+	}
+
+	return nil, false
+}
+
+// encodeNestedWith encodes a dereferenced nested value with the direct encoder of its type when possible, or through the hooks otherwise.
+// The direct encoder is only used without custom hooks, and on values that are neither nil nor interfaces
+func (encoder *neo4goEncoder) encodeNestedWith(direct fieldEncoder, v reflect.Value, path string, state *encodingState) InputStruct {
+	if direct != nil && !encoder.hasCustomHook && v.CanInterface() && !IsNil(v) &&
+		v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface {
+		return direct(encoder, v, path, state)
+	}
+
+	return encoder.encodeNested(v, path, state)
+}
+
+// encodingRootPath returns the path given to the encoded object itself, which is the name of its type
+func encodingRootPath(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+
+	usedType := v.Type()
+	for usedType.Kind() == reflect.Ptr {
+		usedType = usedType.Elem()
+	}
+	if usedType.Name() != "" {
+		return usedType.Name()
+	}
+
+	return usedType.String()
+}
+
+// getDefaultHook returns a composition of all the default encode hook functions used for primitive values
 func (encoder *neo4goEncoder) getDefaultHook() EncodeHookFunc {
 	return ComposeEncodeHookFunc(
 		defaultHookInputStruct, // NOTE This one must be first in order to have the wanted behavior
@@ -94,9 +299,7 @@ func (encoder *neo4goEncoder) getDefaultHook() EncodeHookFunc {
 		defaultHookByteArray,
 		defaultHookDateTime,
 		defaultHookPoint,
-		defaultHookStruct(encoder.options.TagName, encoder),
-		defaultHookArray(encoder),
-		defaultHookMap(encoder),
+		defaultHookTemporal,
 	)
 }
 
@@ -214,6 +417,54 @@ var (
 		return nil, false
 	}
 
+	// The hook that gives the temporal values of the neo4j-go-driver as they are
+	defaultHookTemporal EncodeHookFunc = func(v reflect.Value, i interface{}) (InputStruct, bool) {
+		switch temporal := i.(type) {
+		case neo4j.Date:
+			timeVal := temporal.Time()
+			return NewInputDate(&timeVal), true
+		case neo4j.LocalTime:
+			timeVal := temporal.Time()
+			return NewInputLocalTime(&timeVal), true
+		case neo4j.OffsetTime:
+			timeVal := temporal.Time()
+			return NewInputOffsetTime(&timeVal), true
+		case neo4j.LocalDateTime:
+			timeVal := temporal.Time()
+			return NewInputLocalDateTime(&timeVal), true
+		case neo4j.Duration:
+			return NewInputNeo4jDuration(&temporal), true
+		case *neo4j.Date:
+			if temporal == nil {
+				return NewInputDate(nil), true
+			}
+			timeVal := temporal.Time()
+			return NewInputDate(&timeVal), true
+		case *neo4j.LocalTime:
+			if temporal == nil {
+				return NewInputLocalTime(nil), true
+			}
+			timeVal := temporal.Time()
+			return NewInputLocalTime(&timeVal), true
+		case *neo4j.OffsetTime:
+			if temporal == nil {
+				return NewInputOffsetTime(nil), true
+			}
+			timeVal := temporal.Time()
+			return NewInputOffsetTime(&timeVal), true
+		case *neo4j.LocalDateTime:
+			if temporal == nil {
+				return NewInputLocalDateTime(nil), true
+			}
+			timeVal := temporal.Time()
+			return NewInputLocalDateTime(&timeVal), true
+		case *neo4j.Duration:
+			return NewInputNeo4jDuration(temporal), true
+		default:
+			return nil, false
+		}
+	}
+
 	// The hook that encodes neo4j point values
 	defaultHookPoint EncodeHookFunc = func(v reflect.Value, i interface{}) (InputStruct, bool) {
 		if point, canConvert := i.(neo4j.Point); canConvert {
@@ -224,143 +475,162 @@ var (
 
 		return nil, false
 	}
+)
 
-	// The hook that encodes structs and their exported/tagged fields
-	defaultHookStruct = func(tagName string, encoder Encoder) EncodeHookFunc {
-		return func(v reflect.Value, i interface{}) (InputStruct, bool) {
-			// Verify that the object is a struct
-			usedVal := GetValueElem(v)
-			if !usedVal.IsValid() || usedVal.Kind() != reflect.Struct {
-				return nil, false
-			}
+// encodeStruct encodes a struct and its exported/tagged fields into a map, following the encoding plan of its type
+func (encoder *neo4goEncoder) encodeStruct(usedVal reflect.Value, path string, state *encodingState) InputStruct {
+	plan := getEncodingPlan(encoder.options.TagName, usedVal.Type())
+	resultMap := make(map[string]InputStruct, len(plan.fields))
 
-			usedType := usedVal.Type()
-			resultMap := make(map[string]InputStruct)
-
-			// Run through all of its fields
-			for i := 0; i < usedVal.NumField(); i++ {
-				field := usedType.Field(i)
-
-				// Get the field tag by the name given in encoder options
-				fieldVal := usedVal.FieldByName(field.Name)
-				fieldTag := field.Tag.Get(tagName)
-
-				// If there is no tag on the field, skip it
-				if fieldTag == "" {
-					continue
-				}
-
-				// Separate the values of the tag and find its name and omitempty
-				allTagValues := strings.Split(fieldTag, ",")
-				nameInTag := strings.TrimSpace(allTagValues[0])
-				hasOmitEmpty := false
-				for _, tagValue := range allTagValues {
-					if tagValue == "omitempty" {
-						hasOmitEmpty = true
-					}
-				}
-
-				var key string
-				// If the tag name exists and its value exists, then set the resulting map kay as this name. Else, skip this field
-				if nameInTag != "" && nameInTag != "-" {
-					key = nameInTag
-				} else {
-					continue
-				}
-
-				// If the field is valid and exported, then add it to the resulting map
-				// If the field is the zero value of its type, and omitempty was set for it, then skip it
-				// If there is a problem, set the mapped value as nil
-				fieldVal = GetValueElem(fieldVal)
-				if fieldVal.IsValid() {
-					if fieldVal.IsZero() && hasOmitEmpty {
-						continue
-					} else if fieldVal.CanInterface() {
-						fieldInterface := fieldVal.Interface()
-
-						resultMap[key] = encoder.Encode(fieldInterface)
-					} else {
-						resultMap[key] = nil
-					}
-				} else {
-					resultMap[key] = nil
-				}
-			}
+	for _, fieldPlan := range plan.fields {
+		fieldVal := GetValueElem(usedVal.Field(fieldPlan.index))
+
+		// If the field is valid and exported, then add it to the resulting map
+		// If the field is the zero value of its type, and omitempty was set for it, then skip it
+		// If there is a problem, set the mapped value as nil
+		if !fieldVal.IsValid() {
+			resultMap[fieldPlan.key] = nil
+			continue
+		}
+		if fieldPlan.omitEmpty && fieldVal.IsZero() {
+			continue
+		}
+
+		fieldPath := path + "." + fieldPlan.name
+
+		if fieldPlan.inline {
+			encoder.encodeInline(fieldPlan, fieldVal, fieldPath, state, resultMap)
+			continue
+		}
+
+		if fieldPlan.unexported {
+			resultMap[fieldPlan.key] = nil
+			continue
+		}
 
-			// Every struct will be encoded as maps
-			return NewInputMap(resultMap), true
+		if fieldPlan.temporal != "" {
+			resultMap[fieldPlan.key] = encoder.encodeTemporal(fieldPlan.temporal, fieldVal, fieldPath, state)
+			continue
 		}
+
+		resultMap[fieldPlan.key] = encoder.encodeNestedWith(fieldPlan.direct, fieldVal, fieldPath, state)
 	}
 
-	// The hook that encodes arrays of any type
-	defaultHookArray = func(encoder Encoder) EncodeHookFunc {
-		return func(v reflect.Value, i interface{}) (InputStruct, bool) {
-			usedVal := GetValueElem(v)
+	// Every struct will be encoded as maps
+	return NewInputMap(resultMap)
+}
 
-			// Only use this hook on array-like object
-			if usedVal.Kind() != reflect.Array && usedVal.Kind() != reflect.Slice {
-				return nil, false
-			}
+// encodeInline encodes an inline field and flattens its encoded fields into the map of its parent struct.
+// A nil field adds no value, and a field that is not encoded as a map is recorded as a failure
+func (encoder *neo4goEncoder) encodeInline(fieldPlan fieldEncodingPlan, fieldVal reflect.Value, path string, state *encodingState, resultMap map[string]InputStruct) {
+	if IsNil(fieldVal) {
+		return
+	}
 
-			// Iterate on every item of the array and try to encode it on its own. Put a nil value if it cannot be encoded
-			encodedArray := make([]InputStruct, 0, usedVal.Len())
-			for index := 0; index < usedVal.Len(); index++ {
-				itemVal := usedVal.Index(index)
-				if itemVal.CanInterface() {
-					encodedArray = append(encodedArray, encoder.Encode(itemVal.Interface()))
-				} else {
-					encodedArray = append(encodedArray, nil)
-				}
-			}
+	// An unexported embedded struct cannot be given to the hooks, but its exported fields can still be read
+	if fieldPlan.unexported {
+		encodedField := encoder.encodeStruct(GetValueElem(fieldVal), path, state)
+		for key, val := range encodedField.(*inputMap).Value {
+			resultMap[key] = val
+		}
+		return
+	}
 
-			return NewInputArray(encodedArray), true
+	encodedField := encoder.encodeNestedWith(fieldPlan.direct, fieldVal, path, state)
+	if encodedField == nil {
+		return
+	}
+
+	encodedMap, isMap := encodedField.(*inputMap)
+	if !isMap {
+		state.fail(path, fieldVal)
+		return
+	}
+
+	for key, val := range encodedMap.Value {
+		resultMap[key] = val
+	}
+}
+
+// encodeTemporal encodes a field with a temporal tag option as the neo4j temporal type chosen by the option.
+// The times, or durations for the duration option, can also be in arrays. Any other value is recorded as a failure
+func (encoder *neo4goEncoder) encodeTemporal(temporal string, v reflect.Value, path string, state *encodingState) InputStruct {
+	v = GetValueElem(v)
+	if IsNil(v) {
+		return nil
+	}
+
+	if v.Kind() == reflect.Array || v.Kind() == reflect.Slice {
+		encodedArray := make([]InputStruct, 0, v.Len())
+		for index := 0; index < v.Len(); index++ {
+			itemPath := path + "[" + strconv.Itoa(index) + "]"
+			encodedArray = append(encodedArray, encoder.encodeTemporal(temporal, v.Index(index), itemPath, state))
 		}
+
+		return NewInputArray(encodedArray)
 	}
 
-	// The hook that encodes arrays of any type
-	defaultHookMap = func(encoder Encoder) EncodeHookFunc {
-		return func(v reflect.Value, i interface{}) (InputStruct, bool) {
-			usedVal := GetValueElem(v)
+	if temporal == durationTagOption && v.Type() == durationType {
+		duration := time.Duration(v.Int())
+		return NewInputDuration(&duration)
+	}
 
-			if usedVal.Kind() != reflect.Map {
-				return nil, false
-			}
+	if newTemporalInput, isTime := temporalTagOptions[temporal]; isTime && v.Type() == timeType {
+		timeVal := v.Interface().(time.Time)
+		return newTemporalInput(&timeVal)
+	}
 
-			encodedMap := make(map[string]InputStruct)
-
-			// Iterate on every key/value of the map
-			vMapIter := usedVal.MapRange()
-			for vMapIter.Next() {
-				key := vMapIter.Key()
-				val := vMapIter.Value()
-
-				// Convert the key to string as precisely as we can
-				var keyStr string
-				switch key.Kind() {
-				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-					keyStr = strconv.FormatInt(key.Int(), 10)
-				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-					keyStr = strconv.FormatUint(key.Uint(), 10)
-				case reflect.Float32, reflect.Float64:
-					keyStr = strconv.FormatFloat(key.Float(), 'f', -1, 64)
-				case reflect.Bool:
-					keyStr = strconv.FormatBool(key.Bool())
-				case reflect.Ptr:
-					keyStr = fmt.Sprintf("%v", key.Pointer())
-				case reflect.String:
-					keyStr = key.String()
-				default:
-					keyStr = key.String()
-				}
-
-				if val.CanInterface() {
-					encodedMap[keyStr] = encoder.Encode(val.Interface())
-				} else {
-					encodedMap[keyStr] = nil
-				}
-			}
+	state.failWithError(path, v, fmt.Errorf("the %s tag option cannot be applied to this type", temporal))
+
+	return nil
+}
+
+// encodeArray encodes an array of any type, item by item
+func (encoder *neo4goEncoder) encodeArray(usedVal reflect.Value, path string, state *encodingState) InputStruct {
+	direct := getDirectEncoder(usedVal.Type().Elem())
 
-			return NewInputMap(encodedMap), true
+	// Iterate on every item of the array and try to encode it on its own. Put a nil value if it cannot be encoded
+	encodedArray := make([]InputStruct, 0, usedVal.Len())
+	for index := 0; index < usedVal.Len(); index++ {
+		itemPath := path + "[" + strconv.Itoa(index) + "]"
+		encodedArray = append(encodedArray, encoder.encodeNestedWith(direct, GetValueElem(usedVal.Index(index)), itemPath, state))
+	}
+
+	return NewInputArray(encodedArray)
+}
+
+// encodeMap encodes a map of any type, with its keys converted to strings
+func (encoder *neo4goEncoder) encodeMap(usedVal reflect.Value, path string, state *encodingState) InputStruct {
+	direct := getDirectEncoder(usedVal.Type().Elem())
+	encodedMap := make(map[string]InputStruct, usedVal.Len())
+
+	// Iterate on every key/value of the map
+	vMapIter := usedVal.MapRange()
+	for vMapIter.Next() {
+		key := vMapIter.Key()
+		val := vMapIter.Value()
+
+		// Convert the key to string as precisely as we can
+		var keyStr string
+		switch key.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			keyStr = strconv.FormatInt(key.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			keyStr = strconv.FormatUint(key.Uint(), 10)
+		case reflect.Float32, reflect.Float64:
+			keyStr = strconv.FormatFloat(key.Float(), 'f', -1, 64)
+		case reflect.Bool:
+			keyStr = strconv.FormatBool(key.Bool())
+		case reflect.Ptr:
+			keyStr = fmt.Sprintf("%v", key.Pointer())
+		case reflect.String:
+			keyStr = key.String()
+		default:
+			keyStr = key.String()
 		}
+
+		encodedMap[keyStr] = encoder.encodeNestedWith(direct, GetValueElem(val), path+"["+keyStr+"]", state)
 	}
-)
+
+	return NewInputMap(encodedMap)
+}
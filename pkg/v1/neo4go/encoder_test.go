@@ -0,0 +1,735 @@
+package neo4go
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+type encodedGeo struct {
+	Updates chan int `neo4j:"updates"`
+}
+
+type encodedAddress struct {
+	City string     `neo4j:"city"`
+	Geo  encodedGeo `neo4j:"geo"`
+}
+
+type encodedUser struct {
+	Name    string                 `neo4j:"name"`
+	Address encodedAddress         `neo4j:"address"`
+	Tags    []interface{}          `neo4j:"tags"`
+	Extra   map[string]interface{} `neo4j:"extra,omitempty"`
+	secret  string                 `neo4j:"secret"`
+}
+
+func Test_neo4goEncoder_EncodeWithError(t *testing.T) {
+	city := "Paris"
+	name := "Alice"
+	tag := "admin"
+
+	tests := []struct {
+		name         string
+		strict       bool
+		obj          interface{}
+		want         InputStruct
+		wantFailures []EncodingFailure
+	}{
+		{
+			name: "Should encode a tagged field that is not exported as nil without reporting it",
+			obj:  encodedUser{Name: name, Address: encodedAddress{City: city}, Tags: []interface{}{tag}},
+			want: NewInputMap(map[string]InputStruct{
+				"name": NewInputString(&name),
+				"address": NewInputMap(map[string]InputStruct{
+					"city": NewInputString(&city),
+					"geo":  NewInputMap(map[string]InputStruct{"updates": nil}),
+				}),
+				"tags":   NewInputArray([]InputStruct{NewInputString(&tag)}),
+				"secret": nil,
+			}),
+		},
+		{
+			name: "Should report the path of every value that cannot be encoded",
+			obj: &encodedUser{
+				Name:    name,
+				Address: encodedAddress{City: city, Geo: encodedGeo{Updates: make(chan int)}},
+				Tags:    []interface{}{tag, func() {}},
+				Extra:   map[string]interface{}{"score": complex(1, 2)},
+			},
+			want: NewInputMap(map[string]InputStruct{
+				"name": NewInputString(&name),
+				"address": NewInputMap(map[string]InputStruct{
+					"city": NewInputString(&city),
+					"geo":  NewInputMap(map[string]InputStruct{"updates": nil}),
+				}),
+				"tags":   NewInputArray([]InputStruct{NewInputString(&tag), nil}),
+				"extra":  NewInputMap(map[string]InputStruct{"score": nil}),
+				"secret": nil,
+			}),
+			wantFailures: []EncodingFailure{
+				{Path: "encodedUser.Address.Geo.Updates", GotType: "chan int"},
+				{Path: "encodedUser.Tags[1]", GotType: "func()"},
+				{Path: "encodedUser.Extra[score]", GotType: "complex128"},
+			},
+		},
+		{
+			name:   "Should not refuse a tagged field that is not exported in strict mode",
+			strict: true,
+			obj:    encodedUser{Name: name, secret: "hidden"},
+			want: NewInputMap(map[string]InputStruct{
+				"name": NewInputString(&name),
+				"address": NewInputMap(map[string]InputStruct{
+					"city": NewInputString(new(string)),
+					"geo":  NewInputMap(map[string]InputStruct{"updates": nil}),
+				}),
+				"tags":   nil,
+				"secret": nil,
+			}),
+		},
+		{
+			name:         "Should not return the object in strict mode",
+			strict:       true,
+			obj:          []interface{}{tag, make(chan int)},
+			want:         nil,
+			wantFailures: []EncodingFailure{{Path: "[]interface {}[1]", GotType: "chan int"}},
+		},
+		{
+			name:         "Should report an object that cannot be encoded at all",
+			obj:          func() {},
+			want:         nil,
+			wantFailures: []EncodingFailure{{Path: "func()", GotType: "func()"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder := NewEncoder(&EncoderOptions{Silent: true, Strict: tt.strict})
+
+			got, err := encoder.EncodeWithError(tt.obj)
+			if (err != nil) != (len(tt.wantFailures) > 0) {
+				t.Fatalf("neo4goEncoder.EncodeWithError() error = %v, want failures %v", err, tt.wantFailures)
+			}
+			if err != nil && !IsEncodingError(err) {
+				t.Errorf("neo4goEncoder.EncodeWithError() error = %v, want an Encoding error", err)
+			}
+			if gotFailures := EncodingErrorFailures(err); !reflect.DeepEqual(gotFailures, tt.wantFailures) && len(tt.wantFailures) > 0 {
+				t.Errorf("EncodingErrorFailures() = %v, want %v", gotFailures, tt.wantFailures)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("neo4goEncoder.EncodeWithError() = %v, want %v", got, tt.want)
+			}
+			if gotEncode := encoder.Encode(tt.obj); !reflect.DeepEqual(gotEncode, tt.want) {
+				t.Errorf("neo4goEncoder.Encode() = %v, want %v", gotEncode, tt.want)
+			}
+		})
+	}
+}
+
+type encodedRole string
+
+type encodedMember struct {
+	Role     encodedRole            `neo4j:"role"`
+	Nickname *string                `neo4j:"nickname"`
+	Friends  []*encodedMember       `neo4j:"friends"`
+	Scores   map[string]float32     `neo4j:"scores"`
+	Extra    map[string]interface{} `neo4j:"extra"`
+	Data     []byte                 `neo4j:"data"`
+	Since    time.Time              `neo4j:"since"`
+	Input    InputStruct            `neo4j:"input"`
+}
+
+func Test_neo4goEncoder_EncodingPlan(t *testing.T) {
+	nickname := "Al"
+	since := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		obj  interface{}
+	}{
+		{
+			name: "Should encode the zero value of a struct",
+			obj:  encodedMember{},
+		},
+		{
+			name: "Should encode every field of a struct",
+			obj: &encodedMember{
+				Role:     "admin",
+				Nickname: &nickname,
+				Friends:  []*encodedMember{{Role: "guest"}, nil},
+				Scores:   map[string]float32{"chess": 1.5},
+				Extra:    map[string]interface{}{"level": 3, "nested": encodedMember{Role: "guest"}},
+				Data:     []byte("data"),
+				Since:    since,
+				Input:    NewInputString(&nickname),
+			},
+		},
+		{
+			name: "Should encode the rows of a bulk import",
+			obj:  []benchUser{newBenchUser(), newBenchUser()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A custom hook that never encodes anything makes every value go through the hooks instead of the direct encoders
+			hookEncoder := NewEncoder(&EncoderOptions{
+				EncodeHook: func(v reflect.Value, i interface{}) (InputStruct, bool) {
+					return nil, false
+				},
+			})
+			want, err := hookEncoder.EncodeWithError(tt.obj)
+			if err != nil {
+				t.Fatalf("neo4goEncoder.EncodeWithError() through the hooks error = %v", err)
+			}
+
+			// Encoding twice also uses the cached plans
+			encoder := NewEncoder(nil)
+			for i := 0; i < 2; i++ {
+				got, err := encoder.EncodeWithError(tt.obj)
+				if err != nil {
+					t.Fatalf("neo4goEncoder.EncodeWithError() error = %v", err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("neo4goEncoder.EncodeWithError() = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+type BaseEntity struct {
+	ID      string `neo4j:"id"`
+	Version int    `neo4j:"version"`
+}
+
+type entityAudit struct {
+	CreatedBy string `neo4j:"createdBy"`
+	Version   int    `neo4j:"version"`
+}
+
+type auditedEntity struct {
+	BaseEntity
+	Audit   entityAudit  `neo4j:",squash"`
+	Owner   *BaseEntity  `neo4j:"owner"`
+	Name    string       `neo4j:"name"`
+	Parent  *BaseEntity  `neo4j:"parent,inline"`
+	Skipped *entityAudit `neo4j:"-"`
+}
+
+func Test_neo4goEncoder_Inline(t *testing.T) {
+	id := "1"
+	parentID := "0"
+	createdBy := "admin"
+	name := "Alice"
+	version := int64(2)
+	zero := int64(0)
+
+	tests := []struct {
+		name string
+		obj  interface{}
+		want InputStruct
+	}{
+		{
+			name: "Should flatten the embedded structs and the inline fields, the fields of the struct taking precedence",
+			obj: auditedEntity{
+				BaseEntity: BaseEntity{ID: id, Version: 1},
+				Audit:      entityAudit{CreatedBy: createdBy, Version: 2},
+				Name:       name,
+				Parent:     &BaseEntity{ID: parentID},
+			},
+			want: NewInputMap(map[string]InputStruct{
+				"id":        NewInputString(&parentID),
+				"version":   NewInputInteger(&zero),
+				"createdBy": NewInputString(&createdBy),
+				"owner":     nil,
+				"name":      NewInputString(&name),
+			}),
+		},
+		{
+			name: "Should not flatten a nil inline field",
+			obj: &auditedEntity{
+				BaseEntity: BaseEntity{ID: id, Version: 1},
+				Audit:      entityAudit{CreatedBy: createdBy, Version: 2},
+				Owner:      &BaseEntity{ID: parentID},
+				Name:       name,
+			},
+			want: NewInputMap(map[string]InputStruct{
+				"id":        NewInputString(&id),
+				"version":   NewInputInteger(&version),
+				"createdBy": NewInputString(&createdBy),
+				"owner": NewInputMap(map[string]InputStruct{
+					"id":      NewInputString(&parentID),
+					"version": NewInputInteger(&zero),
+				}),
+				"name": NewInputString(&name),
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEncoder(nil).EncodeWithError(tt.obj)
+			if err != nil {
+				t.Fatalf("neo4goEncoder.EncodeWithError() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("neo4goEncoder.EncodeWithError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type entityKey struct {
+	Key string `neo4j:"key"`
+}
+
+// keyedEntity embeds unexported structs, whose exported fields are promoted
+type keyedEntity struct {
+	entityKey
+	*entityAudit
+	Name string `neo4j:"name"`
+}
+
+func Test_neo4goEncoder_UnexportedEmbedded(t *testing.T) {
+	key := "k1"
+	createdBy := "admin"
+	name := "Alice"
+	version := int64(2)
+
+	tests := []struct {
+		name string
+		obj  interface{}
+		want InputStruct
+	}{
+		{
+			name: "Should flatten the unexported embedded structs",
+			obj:  keyedEntity{entityKey: entityKey{Key: key}, entityAudit: &entityAudit{CreatedBy: createdBy, Version: 2}, Name: name},
+			want: NewInputMap(map[string]InputStruct{
+				"key":       NewInputString(&key),
+				"createdBy": NewInputString(&createdBy),
+				"version":   NewInputInteger(&version),
+				"name":      NewInputString(&name),
+			}),
+		},
+		{
+			name: "Should not flatten a nil unexported embedded struct",
+			obj:  &keyedEntity{entityKey: entityKey{Key: key}, Name: name},
+			want: NewInputMap(map[string]InputStruct{
+				"key":  NewInputString(&key),
+				"name": NewInputString(&name),
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEncoder(&EncoderOptions{Strict: true}).EncodeWithError(tt.obj)
+			if err != nil {
+				t.Fatalf("neo4goEncoder.EncodeWithError() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("neo4goEncoder.EncodeWithError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// stampedEntity embeds structs that are encoded as values, so they must not be flattened
+type stampedEntity struct {
+	time.Time
+	neo4j.Date
+	Name string `neo4j:"name"`
+}
+
+func Test_neo4goEncoder_EmbeddedValues(t *testing.T) {
+	name := "Alice"
+	obj := stampedEntity{Time: time.Now(), Date: neo4j.DateOf(time.Now()), Name: name}
+
+	got, err := NewEncoder(nil).EncodeWithError(obj)
+	if err != nil {
+		t.Fatalf("neo4goEncoder.EncodeWithError() error = %v", err)
+	}
+	want := NewInputMap(map[string]InputStruct{"name": NewInputString(&name)})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("neo4goEncoder.EncodeWithError() = %v, want %v", got, want)
+	}
+}
+
+// testMoney is stored as a string with its currency, through its marshalers
+type testMoney int64
+
+func (m testMoney) MarshalNeo4j() (InputStruct, error) {
+	if m < 0 {
+		return nil, errors.New("negative amount")
+	}
+
+	amount := fmt.Sprintf("%d EUR", m)
+	return NewInputString(&amount), nil
+}
+
+func (m *testMoney) UnmarshalNeo4j(value interface{}) error {
+	amount, isString := value.(string)
+	if !isString {
+		return errors.New("amount is not a string")
+	}
+
+	_, err := fmt.Sscanf(amount, "%d EUR", (*int64)(m))
+	return err
+}
+
+// testLevel is stored as its name, through its marshalers with pointer receivers
+type testLevel struct {
+	value int
+}
+
+func (l *testLevel) MarshalNeo4j() (InputStruct, error) {
+	name := strings.Repeat("+", l.value)
+	return NewInputString(&name), nil
+}
+
+func (l *testLevel) UnmarshalNeo4j(value interface{}) error {
+	l.value = len(value.(string))
+	return nil
+}
+
+type marshaledAccount struct {
+	Balance testMoney  `neo4j:"balance"`
+	Level   *testLevel `neo4j:"level"`
+	IP      net.IP     `neo4j:"ip"`
+}
+
+func Test_neo4goEncoder_Marshalers(t *testing.T) {
+	balance := "12 EUR"
+	level := "++"
+	ip := "10.0.0.1"
+
+	tests := []struct {
+		name             string
+		useTextMarshaler bool
+		obj              interface{}
+		want             InputStruct
+		wantFailures     []EncodingFailure
+	}{
+		{
+			name:             "Should encode the values with their marshalers",
+			useTextMarshaler: true,
+			obj:              marshaledAccount{Balance: 12, Level: &testLevel{value: 2}, IP: net.ParseIP(ip)},
+			want: NewInputMap(map[string]InputStruct{
+				"balance": NewInputString(&balance),
+				"level":   NewInputString(&level),
+				"ip":      NewInputString(&ip),
+			}),
+		},
+		{
+			name: "Should not use the text marshalers unless configured",
+			obj:  marshaledAccount{Balance: 12, IP: net.IP{10, 0, 0, 1}},
+			want: NewInputMap(map[string]InputStruct{
+				"balance": NewInputString(&balance),
+				"level":   nil,
+				"ip":      NewEncoder(nil).Encode([]interface{}{uint8(10), uint8(0), uint8(0), uint8(1)}),
+			}),
+		},
+		{
+			name:         "Should report the error of a marshaler",
+			obj:          &marshaledAccount{Balance: -1},
+			want:         NewInputMap(map[string]InputStruct{"balance": nil, "level": nil, "ip": nil}),
+			wantFailures: []EncodingFailure{{Path: "marshaledAccount.Balance", GotType: "neo4go.testMoney", Err: "negative amount"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEncoder(&EncoderOptions{UseTextMarshaler: tt.useTextMarshaler}).EncodeWithError(tt.obj)
+			if gotFailures := EncodingErrorFailures(err); !reflect.DeepEqual(gotFailures, tt.wantFailures) {
+				t.Errorf("EncodingErrorFailures() = %v, want %v", gotFailures, tt.wantFailures)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("neo4goEncoder.EncodeWithError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type temporalShop struct {
+	Birthday  time.Time       `neo4j:"birthday,date"`
+	OpensAt   *time.Time      `neo4j:"opensAt,localtime"`
+	Holidays  []time.Time     `neo4j:"holidays,date"`
+	OpenFor   time.Duration   `neo4j:"openFor,duration"`
+	UpdatedAt time.Time       `neo4j:"updatedAt"`
+	Name      string          `neo4j:"name,date"`
+	ClosesAt  *time.Time      `neo4j:"closesAt,offsettime,omitempty"`
+	Timeout   time.Duration   `neo4j:"timeout"`
+	Expiry    neo4j.Duration  `neo4j:"expiry"`
+	Since     neo4j.LocalTime `neo4j:"since"`
+}
+
+func Test_neo4goEncoder_Temporal(t *testing.T) {
+	day := time.Date(2020, time.March, 14, 9, 30, 0, 0, time.UTC)
+	openFor := 90 * time.Minute
+	timeout := int64(time.Second)
+	name := "shop"
+	expiry := neo4j.DurationOf(1, 2, 3, 4)
+	since := neo4j.LocalTimeOf(day)
+	sinceTime := since.Time()
+
+	tests := []struct {
+		name         string
+		obj          interface{}
+		want         InputStruct
+		wantFailures []EncodingFailure
+	}{
+		{
+			name: "Should encode the times and durations with the type of their tag option",
+			obj:  temporalShop{Birthday: day, OpensAt: &day, Holidays: []time.Time{day}, OpenFor: openFor, UpdatedAt: day, Name: name, Timeout: time.Second, Expiry: expiry, Since: since},
+			want: NewInputMap(map[string]InputStruct{
+				"birthday":  NewInputDate(&day),
+				"opensAt":   NewInputLocalTime(&day),
+				"holidays":  NewInputArray([]InputStruct{NewInputDate(&day)}),
+				"openFor":   NewInputDuration(&openFor),
+				"updatedAt": NewInputDateTime(&day),
+				"name":      nil,
+				"timeout":   NewInputInteger(&timeout),
+				"expiry":    NewInputNeo4jDuration(&expiry),
+				"since":     NewInputLocalTime(&sinceTime),
+			}),
+			wantFailures: []EncodingFailure{{Path: "temporalShop.Name", GotType: "string", Err: "the date tag option cannot be applied to this type"}},
+		},
+		{
+			name: "Should encode a pointer to a temporal value of the driver like its value",
+			obj:  &since,
+			want: NewInputLocalTime(&sinceTime),
+		},
+		{
+			name: "Should encode a pointer to a duration of the driver like its value",
+			obj:  &expiry,
+			want: NewInputNeo4jDuration(&expiry),
+		},
+		{
+			name: "Should encode a nil pointer to a temporal value of the driver as nil",
+			obj:  (*neo4j.Date)(nil),
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEncoder(nil).EncodeWithError(tt.obj)
+			if gotFailures := EncodingErrorFailures(err); !reflect.DeepEqual(gotFailures, tt.wantFailures) {
+				t.Errorf("EncodingErrorFailures() = %v, want %v", gotFailures, tt.wantFailures)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("neo4goEncoder.EncodeWithError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_temporalInputs_PrimitiveConvert(t *testing.T) {
+	day := time.Date(2020, time.March, 14, 9, 30, 0, 500, time.FixedZone("UTC+2", 2*60*60))
+	duration := 26*time.Hour + 3*time.Second + 7
+
+	tests := []struct {
+		name  string
+		input InputStruct
+		want  interface{}
+	}{
+		{name: "Should convert a date", input: NewInputDate(&day), want: neo4j.DateOf(day)},
+		{name: "Should convert a local time", input: NewInputLocalTime(&day), want: neo4j.LocalTimeOf(day)},
+		{name: "Should convert an offset time", input: NewInputOffsetTime(&day), want: neo4j.OffsetTimeOf(day)},
+		{name: "Should convert a local date time", input: NewInputLocalDateTime(&day), want: neo4j.LocalDateTimeOf(day)},
+		{name: "Should convert a duration", input: NewInputDuration(&duration), want: neo4j.DurationOf(0, 0, 26*60*60+3, 7)},
+		{name: "Should convert a nil date", input: NewInputDate(nil), want: nil},
+		{name: "Should convert a nil duration", input: NewInputDuration(nil), want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.input.(primitiveInputObject).PrimitiveConvert(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PrimitiveConvert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type benchAddress struct {
+	Street  string  `neo4j:"street"`
+	City    string  `neo4j:"city"`
+	Zip     int     `neo4j:"zip"`
+	Lat     float64 `neo4j:"lat"`
+	Lng     float64 `neo4j:"lng"`
+	Primary bool    `neo4j:"primary,omitempty"`
+}
+
+type benchUser struct {
+	ID        int64        `neo4j:"id"`
+	Name      string       `neo4j:"name"`
+	Email     string       `neo4j:"email"`
+	Age       int          `neo4j:"age"`
+	Score     float64      `neo4j:"score"`
+	Active    bool         `neo4j:"active"`
+	Nickname  *string      `neo4j:"nickname,omitempty"`
+	Address   benchAddress `neo4j:"address"`
+	Tags      []string     `neo4j:"tags"`
+	CreatedAt time.Time    `neo4j:"createdAt"`
+	Ignored   string
+}
+
+func newBenchUser() benchUser {
+	return benchUser{
+		ID:        42,
+		Name:      "Alice",
+		Email:     "alice@example.com",
+		Age:       31,
+		Score:     4.5,
+		Active:    true,
+		Address:   benchAddress{Street: "1 Main Street", City: "Paris", Zip: 75001, Lat: 48.86, Lng: 2.34},
+		Tags:      []string{"admin", "beta"},
+		CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// referenceEncode encodes a value like the encoder did before the encoding plans were cached : the tags of every struct are parsed
+// on every encoding, and every field, item and map value goes through all the hooks. It is only kept as a reference for the benchmarks,
+// which compare it with the encoder with : go test -run '^$' -bench 'BenchmarkEncoder' -benchmem ./pkg/v1/neo4go
+func referenceEncode(encoder *neo4goEncoder, v reflect.Value, path string) InputStruct {
+	if !v.CanInterface() {
+		return nil
+	}
+	i := v.Interface()
+	v = reflect.ValueOf(i)
+
+	if encodedObj, canEncode := encoder.hook(v, i); canEncode {
+		return encodedObj
+	}
+	if encodedObj, canEncode := encoder.defaultHook(v, i); canEncode {
+		return encodedObj
+	}
+
+	usedVal := GetValueElem(v)
+	switch usedVal.Kind() {
+	case reflect.Struct:
+		resultMap := make(map[string]InputStruct)
+		for index := 0; index < usedVal.NumField(); index++ {
+			field := usedVal.Type().Field(index)
+			fieldTag := field.Tag.Get(encoder.options.TagName)
+			if fieldTag == "" {
+				continue
+			}
+
+			allTagValues := strings.Split(fieldTag, ",")
+			nameInTag := strings.TrimSpace(allTagValues[0])
+			hasOmitEmpty := false
+			for _, tagValue := range allTagValues {
+				if tagValue == "omitempty" {
+					hasOmitEmpty = true
+				}
+			}
+			if nameInTag == "" || nameInTag == "-" {
+				continue
+			}
+
+			fieldVal := GetValueElem(usedVal.Field(index))
+			if !fieldVal.IsValid() {
+				resultMap[nameInTag] = nil
+				continue
+			}
+			if fieldVal.IsZero() && hasOmitEmpty {
+				continue
+			}
+			resultMap[nameInTag] = referenceEncode(encoder, fieldVal, path+"."+field.Name)
+		}
+		return NewInputMap(resultMap)
+	case reflect.Array, reflect.Slice:
+		encodedArray := make([]InputStruct, 0, usedVal.Len())
+		for index := 0; index < usedVal.Len(); index++ {
+			encodedArray = append(encodedArray, referenceEncode(encoder, usedVal.Index(index), fmt.Sprintf("%s[%d]", path, index)))
+		}
+		return NewInputArray(encodedArray)
+	case reflect.Map:
+		encodedMap := make(map[string]InputStruct)
+		mapIter := usedVal.MapRange()
+		for mapIter.Next() {
+			keyStr := fmt.Sprintf("%v", mapIter.Key().Interface())
+			encodedMap[keyStr] = referenceEncode(encoder, mapIter.Value(), fmt.Sprintf("%s[%s]", path, keyStr))
+		}
+		return NewInputMap(encodedMap)
+	}
+
+	return nil
+}
+
+func Test_referenceEncode(t *testing.T) {
+	encoder := NewEncoder(&EncoderOptions{Silent: true}).(*neo4goEncoder)
+	users := []benchUser{newBenchUser(), newBenchUser()}
+
+	// The reference must encode like the encoder, so that the benchmarks compare the same work
+	if got, want := referenceEncode(encoder, reflect.ValueOf(users), "[]benchUser"), encoder.Encode(users); !reflect.DeepEqual(got, want) {
+		t.Errorf("referenceEncode() = %v, want %v", got, want)
+	}
+}
+
+func BenchmarkEncoder_Encode(b *testing.B) {
+	encoder := NewEncoder(&EncoderOptions{Silent: true})
+	user := newBenchUser()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		encoder.Encode(user)
+	}
+}
+
+func BenchmarkEncoder_Encode_CustomHook(b *testing.B) {
+	encoder := NewEncoder(&EncoderOptions{
+		Silent: true,
+		EncodeHook: func(v reflect.Value, i interface{}) (InputStruct, bool) {
+			return nil, false
+		},
+	})
+	user := newBenchUser()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		encoder.Encode(user)
+	}
+}
+
+func BenchmarkEncoder_Encode_Rows(b *testing.B) {
+	encoder := NewEncoder(&EncoderOptions{Silent: true})
+	users := make([]benchUser, 1000)
+	for i := range users {
+		users[i] = newBenchUser()
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		encoder.Encode(users)
+	}
+}
+
+func BenchmarkEncoder_Encode_Reference(b *testing.B) {
+	encoder := NewEncoder(&EncoderOptions{Silent: true}).(*neo4goEncoder)
+	user := reflect.ValueOf(newBenchUser())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		referenceEncode(encoder, user, "benchUser")
+	}
+}
+
+func BenchmarkEncoder_Encode_Rows_Reference(b *testing.B) {
+	encoder := NewEncoder(&EncoderOptions{Silent: true}).(*neo4goEncoder)
+	users := make([]benchUser, 1000)
+	for i := range users {
+		users[i] = newBenchUser()
+	}
+	usersVal := reflect.ValueOf(users)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		referenceEncode(encoder, usersVal, "[]benchUser")
+	}
+}
@@ -0,0 +1,278 @@
+package neo4go
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// fieldEncoder represents a function that encodes a field value without going through the hooks of the encoder
+type fieldEncoder func(encoder *neo4goEncoder, v reflect.Value, path string, state *encodingState) InputStruct
+
+// fieldEncodingPlan represents the way to encode a single tagged field of a struct type
+type fieldEncodingPlan struct {
+	// The index of the field in its struct
+	index int
+
+	// The Go name of the field, used in the paths of the encoding failures
+	name string
+
+	// The key of the field in the encoded map
+	key string
+
+	// Tells if the field is skipped when it is the zero value of its type
+	omitEmpty bool
+
+	// Tells if the encoded fields of the field are flattened into the parent map, instead of being nested under its key
+	inline bool
+
+	// Tells if mapstructure squashes the field when decoding, as it is an embedded struct or has the squash option in its tag
+	squash bool
+
+	// Tells if the field is not exported, so that it cannot be read and is encoded as nil
+	unexported bool
+
+	// The temporal tag option of the field, which chooses the neo4j temporal type of its times or durations, or an empty string
+	temporal string
+
+	// The function that encodes the field directly when the encoder has no custom hook, or nil if the field must go through the hooks
+	direct fieldEncoder
+}
+
+// encodingPlan represents the way to encode a struct type, compiled once per type and tag name
+type encodingPlan struct {
+	// The plans of the tagged fields of the struct. The inline fields come first, so that the fields of the struct itself take precedence over theirs
+	fields []fieldEncodingPlan
+}
+
+// encodingPlanKey identifies an encoding plan, as the same struct type is encoded differently depending on the tag name
+type encodingPlanKey struct {
+	// The tag name used to find the fields of the struct
+	tagName string
+
+	// The struct type
+	structType reflect.Type
+}
+
+// durationTagOption is the tag option that encodes a time.Duration field as a neo4j Duration instead of an integer of nanoseconds
+const durationTagOption = "duration"
+
+// temporalTagOptions are the tag options that choose the neo4j temporal type of a time.Time field, with the input constructor of this type
+var temporalTagOptions = map[string]func(*time.Time) InputStruct{
+	"datetime":      NewInputDateTime,
+	"date":          NewInputDate,
+	"localtime":     NewInputLocalTime,
+	"offsettime":    NewInputOffsetTime,
+	"localdatetime": NewInputLocalDateTime,
+}
+
+// encodingPlans stores the compiled encoding plans by encodingPlanKey, shared by every encoder
+var encodingPlans sync.Map
+
+// directEncoders stores the direct encoders of the types of the fields, array items and map values by type, shared by every encoder.
+// A type that cannot be encoded directly is stored with a nil encoder
+var directEncoders sync.Map
+
+// The types that are encoded by the default hooks in a specific way, so that they cannot be encoded directly
+var (
+	inputStructType    = reflect.TypeOf((*InputStruct)(nil)).Elem()
+	neo4jMarshalerType = reflect.TypeOf((*Neo4jMarshaler)(nil)).Elem()
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType           = reflect.TypeOf(time.Time{})
+	pointType          = reflect.TypeOf(neo4j.Point{})
+	byteArrayType      = reflect.TypeOf([]byte(nil))
+	durationType       = reflect.TypeOf(time.Duration(0))
+
+	// The temporal types of the neo4j-go-driver, given as they are to the driver
+	temporalTypes = map[reflect.Type]bool{
+		reflect.TypeOf(neo4j.Date{}):          true,
+		reflect.TypeOf(neo4j.LocalTime{}):     true,
+		reflect.TypeOf(neo4j.OffsetTime{}):    true,
+		reflect.TypeOf(neo4j.LocalDateTime{}): true,
+		reflect.TypeOf(neo4j.Duration{}):      true,
+	}
+)
+
+// getEncodingPlan returns the encoding plan of the struct type for the given tag name, compiling it on first use
+func getEncodingPlan(tagName string, structType reflect.Type) *encodingPlan {
+	key := encodingPlanKey{tagName: tagName, structType: structType}
+	if plan, exists := encodingPlans.Load(key); exists {
+		return plan.(*encodingPlan)
+	}
+
+	// Concurrent compilations of the same plan give the same result, so the first one stored is kept
+	plan, _ := encodingPlans.LoadOrStore(key, compileEncodingPlan(tagName, structType))
+
+	return plan.(*encodingPlan)
+}
+
+// compileEncodingPlan parses the tags of every field of the struct type and finds how each field can be encoded
+func compileEncodingPlan(tagName string, structType reflect.Type) *encodingPlan {
+	plan := &encodingPlan{}
+
+	inlineFields := make([]fieldEncodingPlan, 0)
+	ownFields := make([]fieldEncodingPlan, 0, structType.NumField())
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldTag := field.Tag.Get(tagName)
+
+		// Separate the values of the tag and find its name, omitempty and inline
+		allTagValues := strings.Split(fieldTag, ",")
+		nameInTag := strings.TrimSpace(allTagValues[0])
+		hasOmitEmpty := false
+		hasInline := false
+		hasSquash := false
+		temporal := ""
+		for _, tagValue := range allTagValues[1:] {
+			tagValue = strings.TrimSpace(tagValue)
+			switch tagValue {
+			case "omitempty":
+				hasOmitEmpty = true
+			case "inline":
+				hasInline = true
+			case "squash":
+				hasInline = true
+				hasSquash = true
+			default:
+				if _, isTemporal := temporalTagOptions[tagValue]; isTemporal || tagValue == durationTagOption {
+					temporal = tagValue
+				}
+			}
+		}
+
+		// Embedded structs without a tag are flattened like inline fields
+		if fieldTag == "" && isEmbeddedStruct(field) {
+			hasInline = true
+			hasSquash = true
+		}
+
+		// If there is no tag on the field, or if the field is ignored, skip it.
+		// Also skip the fields that have no name in their tag, unless they are inline
+		if (fieldTag == "" && !hasInline) || nameInTag == "-" || (nameInTag == "" && !hasInline) {
+			continue
+		}
+
+		// Unexported fields cannot be read, so they are encoded as nil without being reported, and add nothing when inline.
+		// The unexported embedded structs are still flattened like encoding/json does, as their exported fields are promoted
+		isUnexported := field.PkgPath != ""
+		if isUnexported && hasInline && !isEmbeddedStruct(field) {
+			continue
+		}
+
+		fieldPlan := fieldEncodingPlan{
+			index:      i,
+			name:       field.Name,
+			key:        nameInTag,
+			omitEmpty:  hasOmitEmpty,
+			inline:     hasInline,
+			squash:     hasSquash,
+			unexported: isUnexported,
+			temporal:   temporal,
+		}
+
+		// The fields with a temporal option are encoded depending on their option instead
+		if !isUnexported && temporal == "" {
+			fieldPlan.direct = getDirectEncoder(field.Type)
+		}
+
+		if hasInline {
+			inlineFields = append(inlineFields, fieldPlan)
+		} else {
+			ownFields = append(ownFields, fieldPlan)
+		}
+	}
+	plan.fields = append(inlineFields, ownFields...)
+
+	return plan
+}
+
+// isEmbeddedStruct tells if the field is an embedded struct, or pointer to struct, exported or not.
+// The embedded structs that the default hooks encode as a value, such as time.Time or the marshalers, are not flattened
+func isEmbeddedStruct(field reflect.StructField) bool {
+	if !field.Anonymous {
+		return false
+	}
+
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	return fieldType.Kind() == reflect.Struct && !isSpecificType(fieldType)
+}
+
+// isSpecificType tells if the values of a type are encoded by a default hook in a specific way
+func isSpecificType(valueType reflect.Type) bool {
+	for _, specificType := range []reflect.Type{inputStructType, neo4jMarshalerType, textMarshalerType} {
+		if valueType.Implements(specificType) || reflect.PtrTo(valueType).Implements(specificType) {
+			return true
+		}
+	}
+
+	return valueType == timeType || valueType == pointType || valueType == byteArrayType || temporalTypes[valueType]
+}
+
+// getDirectEncoder returns the direct encoder of the values of a type, finding it on first use
+func getDirectEncoder(valueType reflect.Type) fieldEncoder {
+	if direct, exists := directEncoders.Load(valueType); exists {
+		return direct.(fieldEncoder)
+	}
+
+	direct, _ := directEncoders.LoadOrStore(valueType, directFieldEncoder(valueType))
+
+	return direct.(fieldEncoder)
+}
+
+// directFieldEncoder returns the function that encodes the values of a field type like the default hooks would, but without trying every hook.
+// It returns nil for the types that a default hook handles specifically, and for interfaces whose concrete type is only known at encoding
+func directFieldEncoder(fieldType reflect.Type) fieldEncoder {
+	// Fields are dereferenced before being encoded, so only the pointed type matters
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	// The marshalers are only called through the encoder
+	if isSpecificType(fieldType) {
+		return nil
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(encoder *neo4goEncoder, v reflect.Value, path string, state *encodingState) InputStruct {
+			objInt := v.Int()
+			return NewInputInteger(&objInt)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(encoder *neo4goEncoder, v reflect.Value, path string, state *encodingState) InputStruct {
+			objInt := v.Uint()
+			return NewInputUnsignedInteger(&objInt)
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(encoder *neo4goEncoder, v reflect.Value, path string, state *encodingState) InputStruct {
+			objFloat := v.Float()
+			return NewInputFloat(&objFloat)
+		}
+	case reflect.Bool:
+		return func(encoder *neo4goEncoder, v reflect.Value, path string, state *encodingState) InputStruct {
+			objBool := v.Bool()
+			return NewInputBool(&objBool)
+		}
+	case reflect.String:
+		return func(encoder *neo4goEncoder, v reflect.Value, path string, state *encodingState) InputStruct {
+			objString := v.String()
+			return NewInputString(&objString)
+		}
+	case reflect.Struct:
+		return (*neo4goEncoder).encodeStruct
+	case reflect.Array, reflect.Slice:
+		return (*neo4goEncoder).encodeArray
+	case reflect.Map:
+		return (*neo4goEncoder).encodeMap
+	default:
+		return nil
+	}
+}
@@ -81,6 +81,87 @@ func IsTransactionError(err error) bool {
 	return canConvert
 }
 
+// IsContextError tells if the error is a neo4go Context error
+func IsContextError(err error) bool {
+	_, canConvert := err.(*internalErr.ContextError)
+	return canConvert
+}
+
+// IsParameterError tells if the error is a neo4go Parameter error
+func IsParameterError(err error) bool {
+	_, canConvert := err.(*internalErr.ParameterError)
+	return canConvert
+}
+
+// IsBatchError tells if the error is a neo4go Batch error
+func IsBatchError(err error) bool {
+	_, canConvert := err.(*internalErr.BatchError)
+	return canConvert
+}
+
+// BatchErrorIndex returns the index of the statement that failed in a batch, and false if the error is not a Batch error
+func BatchErrorIndex(err error) (int, bool) {
+	batchErr, canConvert := err.(*internalErr.BatchError)
+	if !canConvert {
+		return -1, false
+	}
+
+	return batchErr.Index, true
+}
+
+// BatchErrorCause returns the error of the statement that failed in a batch, or nil if the error is not a Batch error
+func BatchErrorCause(err error) Neo4GoError {
+	batchErr, canConvert := err.(*internalErr.BatchError)
+	if !canConvert || batchErr.Cause == nil {
+		return nil
+	}
+
+	return toDriverError(batchErr.Cause)
+}
+
+// IsShutdownError tells if the error is a neo4go Shutdown error
+func IsShutdownError(err error) bool {
+	_, canConvert := err.(*internalErr.ShutdownError)
+	return canConvert
+}
+
+// ShutdownErrorFailures returns the errors of every resource that could not be released while shutting down the manager,
+// or nil if the error is not a Shutdown error
+func ShutdownErrorFailures(err error) []Neo4GoError {
+	shutdownErr, canConvert := err.(*internalErr.ShutdownError)
+	if !canConvert || len(shutdownErr.Failures) == 0 {
+		return nil
+	}
+
+	failures := make([]Neo4GoError, 0, len(shutdownErr.Failures))
+	for _, failure := range shutdownErr.Failures {
+		failures = append(failures, toDriverError(failure))
+	}
+
+	return failures
+}
+
+// IsEncodingError tells if the error is a neo4go Encoding error
+func IsEncodingError(err error) bool {
+	_, canConvert := err.(*internalErr.EncodingError)
+	return canConvert
+}
+
+// EncodingErrorFailures returns the path and type of every value that could not be encoded, or nil if the error is not an Encoding error
+func EncodingErrorFailures(err error) []EncodingFailure {
+	encodingErr, canConvert := err.(*internalErr.EncodingError)
+	if !canConvert || len(encodingErr.Failures) == 0 {
+		return nil
+	}
+
+	failures := make([]EncodingFailure, 0, len(encodingErr.Failures))
+	for _, failure := range encodingErr.Failures {
+		failures = append(failures, EncodingFailure{Path: failure.Path, GotType: failure.GotType, Err: failure.Err})
+	}
+
+	return failures
+}
+
 // IsUnknownError tells if the error is a neo4go Unknown error
 func IsUnknownError(err error) bool {
 	_, canConvert := err.(*internalErr.UnknownError)
@@ -122,3 +203,8 @@ func IsServiceUnavailableError(err error) bool {
 	_, canConvert := err.(*internalErr.UnavailableError)
 	return canConvert
 }
+
+// IsRetryableError tells if the error is temporary, so that the transaction that triggered it can be retried
+func IsRetryableError(err error) bool {
+	return IsTransientError(err) || IsSessionError(err) || IsServiceUnavailableError(err)
+}
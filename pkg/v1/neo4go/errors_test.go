@@ -199,6 +199,175 @@ func TestIsTransactionError(t *testing.T) {
 	}
 }
 
+func TestIsContextError(t *testing.T) {
+	type args struct {
+		err error
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "Should detect Context error",
+			args: args{
+				err: &internalErr.ContextError{},
+			},
+			want: true,
+		},
+		{
+			name: "Should not detect basic error",
+			args: args{
+				err: errors.New(""),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsContextError(tt.args.err); got != tt.want {
+				t.Errorf("IsContextError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsParameterError(t *testing.T) {
+	type args struct {
+		err error
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "Should detect Parameter error",
+			args: args{
+				err: &internalErr.ParameterError{},
+			},
+			want: true,
+		},
+		{
+			name: "Should not detect basic error",
+			args: args{
+				err: errors.New(""),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsParameterError(tt.args.err); got != tt.want {
+				t.Errorf("IsParameterError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsBatchError(t *testing.T) {
+	type args struct {
+		err error
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "Should detect Batch error",
+			args: args{
+				err: &internalErr.BatchError{},
+			},
+			want: true,
+		},
+		{
+			name: "Should not detect basic error",
+			args: args{
+				err: errors.New(""),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBatchError(tt.args.err); got != tt.want {
+				t.Errorf("IsBatchError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchErrorIndex(t *testing.T) {
+	type args struct {
+		err error
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      int
+		wantBatch bool
+	}{
+		{
+			name: "Should return the index of a Batch error",
+			args: args{
+				err: &internalErr.BatchError{Index: 3},
+			},
+			want:      3,
+			wantBatch: true,
+		},
+		{
+			name: "Should not return an index for a basic error",
+			args: args{
+				err: errors.New(""),
+			},
+			want:      -1,
+			wantBatch: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotBatch := BatchErrorIndex(tt.args.err)
+			if got != tt.want || gotBatch != tt.wantBatch {
+				t.Errorf("BatchErrorIndex() = %v, %v, want %v, %v", got, gotBatch, tt.want, tt.wantBatch)
+			}
+		})
+	}
+}
+
+func TestIsEncodingError(t *testing.T) {
+	type args struct {
+		err error
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "Should detect Encoding error",
+			args: args{
+				err: &internalErr.EncodingError{},
+			},
+			want: true,
+		},
+		{
+			name: "Should not detect basic error",
+			args: args{
+				err: errors.New(""),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsEncodingError(tt.args.err); got != tt.want {
+				t.Errorf("IsEncodingError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIsUnknownError(t *testing.T) {
 	type args struct {
 		err error
@@ -429,3 +598,57 @@ func TestIsServiceUnavailableError(t *testing.T) {
 		})
 	}
 }
+
+func TestIsRetryableError(t *testing.T) {
+	type args struct {
+		err error
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "Should detect Transient error",
+			args: args{
+				err: &internalErr.TransientError{},
+			},
+			want: true,
+		},
+		{
+			name: "Should detect Session error",
+			args: args{
+				err: &internalErr.SessionError{},
+			},
+			want: true,
+		},
+		{
+			name: "Should detect Service Unavailable error",
+			args: args{
+				err: &internalErr.UnavailableError{},
+			},
+			want: true,
+		},
+		{
+			name: "Should not detect Client error",
+			args: args{
+				err: &internalErr.ClientError{},
+			},
+			want: false,
+		},
+		{
+			name: "Should not detect basic error",
+			args: args{
+				err: errors.New(""),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryableError(tt.args.err); got != tt.want {
+				t.Errorf("IsRetryableError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package neo4go
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// fakeRecord is an in-memory implementation of neo4j.Record
+type fakeRecord struct {
+	keys   []string
+	values []interface{}
+}
+
+func (r *fakeRecord) Keys() []string {
+	return r.keys
+}
+
+func (r *fakeRecord) Values() []interface{} {
+	return r.values
+}
+
+func (r *fakeRecord) Get(key string) (interface{}, bool) {
+	for i, k := range r.keys {
+		if k == key {
+			return r.values[i], true
+		}
+	}
+	return nil, false
+}
+
+func (r *fakeRecord) GetByIndex(index int) interface{} {
+	return r.values[index]
+}
+
+// fakeResult is an in-memory implementation of neo4j.Result that, like the real driver,
+// cannot be read anymore once its session is closed
+type fakeResult struct {
+	session *fakeSession
+	records []neo4j.Record
+	current neo4j.Record
+	err     error
+	endErr  error
+}
+
+func (r *fakeResult) Keys() ([]string, error) {
+	if len(r.records) == 0 {
+		return []string{}, nil
+	}
+	return r.records[0].Keys(), nil
+}
+
+func (r *fakeResult) Next() bool {
+	if r.session != nil && r.session.closed {
+		r.err = errors.New("session is closed")
+	}
+	if r.err == nil && len(r.records) == 0 {
+		r.err = r.endErr
+	}
+	if r.err != nil || len(r.records) == 0 {
+		r.current = nil
+		return false
+	}
+
+	r.current = r.records[0]
+	r.records = r.records[1:]
+	return true
+}
+
+func (r *fakeResult) Err() error {
+	return r.err
+}
+
+func (r *fakeResult) Record() neo4j.Record {
+	return r.current
+}
+
+func (r *fakeResult) Summary() (neo4j.ResultSummary, error) {
+	return nil, r.err
+}
+
+func (r *fakeResult) Consume() (neo4j.ResultSummary, error) {
+	for r.Next() {
+	}
+	return nil, r.err
+}
+
+// fakeDriver is an in-memory implementation of Driver whose sessions return the configured records
+type fakeDriver struct {
+	records    []neo4j.Record
+	runErr     error
+	runErrs    []error
+	resultErr  error
+	runDelay   time.Duration
+	runBlock   chan struct{}
+	connectErr error
+	closeErr   error
+	sessions   []*fakeSession
+	closed     bool
+	mutex      sync.Mutex
+}
+
+func (d *fakeDriver) NewSession(config neo4j.SessionConfig) (Session, error) {
+	session := &fakeSession{driver: d, config: config, records: d.records, runErr: d.runErr, resultErr: d.resultErr, closeErr: d.closeErr}
+
+	d.mutex.Lock()
+	d.sessions = append(d.sessions, session)
+	d.mutex.Unlock()
+
+	return session, nil
+}
+
+// nextRunErr pops the next error of the run errors queue, which fails transaction queries one after another
+func (d *fakeDriver) nextRunErr() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if len(d.runErrs) == 0 {
+		return nil
+	}
+	err := d.runErrs[0]
+	d.runErrs = d.runErrs[1:]
+	return err
+}
+
+func (d *fakeDriver) VerifyConnectivity() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.connectErr
+}
+
+// setConnectErr changes the error of the connectivity checks, which may run concurrently in the background
+func (d *fakeDriver) setConnectErr(err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.connectErr = err
+}
+
+func (d *fakeDriver) Close() error {
+	d.closed = true
+	return d.closeErr
+}
+
+// fakeSession is an in-memory implementation of Session that records how it was used
+type fakeSession struct {
+	driver       *fakeDriver
+	config       neo4j.SessionConfig
+	records      []neo4j.Record
+	runErr       error
+	resultErr    error
+	queries      []string
+	transactions []*fakeTransaction
+	closeErr     error
+	closed       bool
+	closeCount   int
+	mutex        sync.Mutex
+}
+
+func (s *fakeSession) LastBookmark() string {
+	return "bookmark"
+}
+
+func (s *fakeSession) BeginTransaction(configurers ...func(*neo4j.TransactionConfig)) (Transaction, error) {
+	tx := &fakeTransaction{session: s}
+	s.transactions = append(s.transactions, tx)
+	return tx, nil
+}
+
+func (s *fakeSession) Run(cypher string, params map[string]interface{}, configurers ...func(*neo4j.TransactionConfig)) (neo4j.Result, error) {
+	s.queries = append(s.queries, cypher)
+	if s.runErr != nil {
+		return nil, s.runErr
+	}
+	return &fakeResult{session: s, records: s.records, endErr: s.resultErr}, nil
+}
+
+func (s *fakeSession) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.closed = true
+	s.closeCount++
+	return s.closeErr
+}
+
+// isClosed tells if the session was closed, which may happen in background
+func (s *fakeSession) isClosed() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.closed
+}
+
+// fakeTransaction is an in-memory implementation of Transaction that records how it was ended
+type fakeTransaction struct {
+	session    *fakeSession
+	queries    []string
+	params     []map[string]interface{}
+	committed  bool
+	rolledBack bool
+	closed     bool
+	runBlock   chan struct{}
+}
+
+func (tx *fakeTransaction) Run(cypher string, params map[string]interface{}) (neo4j.Result, error) {
+	// A blocked run simulates a query stuck in the database
+	if tx.runBlock != nil {
+		<-tx.runBlock
+	}
+	if tx.session != nil && tx.session.driver != nil && tx.session.driver.runBlock != nil {
+		<-tx.session.driver.runBlock
+	}
+	if tx.session != nil && tx.session.driver != nil && tx.session.driver.runDelay > 0 {
+		time.Sleep(tx.session.driver.runDelay)
+	}
+	tx.queries = append(tx.queries, cypher)
+	tx.params = append(tx.params, params)
+	if tx.session == nil {
+		return &fakeResult{}, nil
+	}
+	if tx.session.driver != nil {
+		if err := tx.session.driver.nextRunErr(); err != nil {
+			return nil, err
+		}
+	}
+	if tx.session.runErr != nil {
+		return nil, tx.session.runErr
+	}
+	return &fakeResult{session: tx.session, records: tx.session.records}, nil
+}
+
+func (tx *fakeTransaction) Commit() error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeTransaction) Rollback() error {
+	tx.rolledBack = true
+	return nil
+}
+
+func (tx *fakeTransaction) Close() error {
+	tx.closed = true
+	if !tx.committed {
+		tx.rolledBack = true
+	}
+	return nil
+}
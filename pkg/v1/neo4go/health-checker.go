@@ -0,0 +1,156 @@
+package neo4go
+
+import (
+	"time"
+)
+
+// ConnectionState represents whether a manager can reach its database, as seen by its last connectivity check
+type ConnectionState uint
+
+const (
+	// UNKNOWN_STATE is the state of a manager whose connectivity was never checked
+	UNKNOWN_STATE ConnectionState = iota
+
+	// CONNECTED_STATE is the state of a manager whose last connectivity check succeeded
+	CONNECTED_STATE
+
+	// DISCONNECTED_STATE is the state of a manager whose last connectivity check failed, or that is shutting down
+	DISCONNECTED_STATE
+)
+
+// String returns the name of the connection state
+func (state ConnectionState) String() string {
+	switch state {
+	case CONNECTED_STATE:
+		return "connected"
+	case DISCONNECTED_STATE:
+		return "disconnected"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnectionStateCallback represents a function called when the connection state of a manager changes
+type ConnectionStateCallback func(previous ConnectionState, current ConnectionState)
+
+// stateChange represents a change of the connection state, with the callbacks registered when it happened
+type stateChange struct {
+	// The state before the change
+	previous ConnectionState
+
+	// The state after the change
+	current ConnectionState
+
+	// The callbacks to call for this change
+	callbacks []ConnectionStateCallback
+}
+
+// State returns the connection state of the manager, as seen by its last connectivity check. It does not reach the database
+func (m *manager) State() ConnectionState {
+	m.stateMutex.RLock()
+	defer m.stateMutex.RUnlock()
+
+	return m.state
+}
+
+// OnConnectionStateChange registers a function called after each change of the connection state of the manager
+func (m *manager) OnConnectionStateChange(callback ConnectionStateCallback) {
+	if callback == nil {
+		return
+	}
+
+	m.stateMutex.Lock()
+	defer m.stateMutex.Unlock()
+
+	m.stateCallbacks = append(m.stateCallbacks, callback)
+}
+
+// checkConnection checks the connectivity of the current driver, then updates the connection state of the manager.
+// A shutting down manager is always disconnected, without any check
+func (m *manager) checkConnection() ConnectionState {
+	newState := m.updateConnectionState()
+
+	// The callbacks are called without holding the check mutex, so that they can check the connectivity or close the manager
+	m.dispatchStateChanges()
+
+	return newState
+}
+
+// updateConnectionState checks the connectivity and stores the new state, queueing the change of state if there is one.
+// Checks are serialized, so that the changes are queued in the order they happen
+func (m *manager) updateConnectionState() ConnectionState {
+	m.checkMutex.Lock()
+	defer m.checkMutex.Unlock()
+
+	newState := DISCONNECTED_STATE
+	if !m.isShuttingDown() {
+		usedDriver := m.currentDriver()
+		if usedDriver != nil && usedDriver.VerifyConnectivity() == nil {
+			newState = CONNECTED_STATE
+		}
+	}
+
+	m.stateMutex.Lock()
+	previousState := m.state
+	m.state = newState
+	callbacks := make([]ConnectionStateCallback, len(m.stateCallbacks))
+	copy(callbacks, m.stateCallbacks)
+	m.stateMutex.Unlock()
+
+	if previousState != newState {
+		m.dispatchMutex.Lock()
+		m.pendingStateChanges = append(m.pendingStateChanges, stateChange{previous: previousState, current: newState, callbacks: callbacks})
+		m.dispatchMutex.Unlock()
+	}
+
+	return newState
+}
+
+// dispatchStateChanges calls the callbacks of the pending changes of state, one change after another.
+// If another goroutine is already calling them, it is left to call the new changes too, so that their order is kept
+func (m *manager) dispatchStateChanges() {
+	m.dispatchMutex.Lock()
+	if m.dispatchingStateChanges {
+		m.dispatchMutex.Unlock()
+		return
+	}
+	m.dispatchingStateChanges = true
+
+	for len(m.pendingStateChanges) > 0 {
+		change := m.pendingStateChanges[0]
+		m.pendingStateChanges = m.pendingStateChanges[1:]
+		m.dispatchMutex.Unlock()
+
+		for _, callback := range change.callbacks {
+			callback(change.previous, change.current)
+		}
+
+		m.dispatchMutex.Lock()
+	}
+
+	m.dispatchingStateChanges = false
+	m.dispatchMutex.Unlock()
+}
+
+// runHealthChecker periodically checks the connectivity of the manager until the stop channel is closed
+func (m *manager) runHealthChecker(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m.checkConnection()
+		}
+	}
+}
+
+// isShuttingDown tells if the manager stopped accepting new queries and transactions
+func (m *manager) isShuttingDown() bool {
+	m.operationsMutex.Lock()
+	defer m.operationsMutex.Unlock()
+
+	return m.shuttingDown
+}
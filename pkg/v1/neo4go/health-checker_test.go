@@ -0,0 +1,201 @@
+package neo4go
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConnectionState_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		state ConnectionState
+		want  string
+	}{
+		{name: "Should name the unknown state", state: UNKNOWN_STATE, want: "unknown"},
+		{name: "Should name the connected state", state: CONNECTED_STATE, want: "connected"},
+		{name: "Should name the disconnected state", state: DISCONNECTED_STATE, want: "disconnected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.String(); got != tt.want {
+				t.Errorf("ConnectionState.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// stateRecorder records the transitions given to a connection state callback
+type stateRecorder struct {
+	transitions [][2]ConnectionState
+	changed     chan struct{}
+	mutex       sync.Mutex
+}
+
+func newStateRecorder() *stateRecorder {
+	return &stateRecorder{changed: make(chan struct{}, 10)}
+}
+
+func (r *stateRecorder) callback(previous ConnectionState, current ConnectionState) {
+	r.mutex.Lock()
+	r.transitions = append(r.transitions, [2]ConnectionState{previous, current})
+	r.mutex.Unlock()
+
+	r.changed <- struct{}{}
+}
+
+func (r *stateRecorder) wait(t *testing.T) {
+	t.Helper()
+
+	select {
+	case <-r.changed:
+	case <-time.After(time.Second):
+		t.Fatalf("the connection state did not change")
+	}
+}
+
+func (r *stateRecorder) got() [][2]ConnectionState {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([][2]ConnectionState{}, r.transitions...)
+}
+
+func Test_manager_State(t *testing.T) {
+	tests := []struct {
+		name       string
+		connectErr error
+		want       ConnectionState
+		wantCalls  int
+	}{
+		{
+			name: "Should stay connected when the database can be reached",
+			want: CONNECTED_STATE,
+		},
+		{
+			name:       "Should become disconnected when the database cannot be reached",
+			connectErr: errors.New("A typical error"),
+			want:       DISCONNECTED_STATE,
+			wantCalls:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, driver := newFakeManager()
+			defer m.Close()
+
+			if got := m.State(); got != CONNECTED_STATE {
+				t.Fatalf("manager.State() after creation = %v, want %v", got, CONNECTED_STATE)
+			}
+
+			recorder := newStateRecorder()
+			m.OnConnectionStateChange(recorder.callback)
+
+			driver.setConnectErr(tt.connectErr)
+			if got := m.IsConnected(); got != (tt.want == CONNECTED_STATE) {
+				t.Errorf("manager.IsConnected() = %v, want %v", got, tt.want == CONNECTED_STATE)
+			}
+			if got := m.State(); got != tt.want {
+				t.Errorf("manager.State() = %v, want %v", got, tt.want)
+			}
+			if got := len(recorder.got()); got != tt.wantCalls {
+				t.Errorf("callback was called %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func Test_manager_HealthChecker(t *testing.T) {
+	driver := &fakeDriver{}
+	m, err := NewManagerWithDriver(ManagerOptions{
+		URI:                 "bolt://localhost:7687",
+		DatabaseName:        "neo4j",
+		HealthCheckInterval: time.Millisecond,
+	}, driver)
+	if err != nil {
+		t.Fatalf("NewManagerWithDriver() error = %v", err)
+	}
+
+	recorder := newStateRecorder()
+	m.OnConnectionStateChange(recorder.callback)
+
+	// The background checks must notice the database going down, then up again
+	driver.setConnectErr(errors.New("A typical error"))
+	recorder.wait(t)
+	if got := m.State(); got != DISCONNECTED_STATE {
+		t.Errorf("manager.State() while the database is down = %v, want %v", got, DISCONNECTED_STATE)
+	}
+
+	driver.setConnectErr(nil)
+	recorder.wait(t)
+	if got := m.State(); got != CONNECTED_STATE {
+		t.Errorf("manager.State() once the database is up = %v, want %v", got, CONNECTED_STATE)
+	}
+
+	// Closing the manager disconnects it right away, and stops the background checks
+	if err := m.Close(); err != nil {
+		t.Fatalf("manager.Close() error = %v", err)
+	}
+	if got := m.State(); got != DISCONNECTED_STATE {
+		t.Errorf("manager.State() after Close = %v, want %v", got, DISCONNECTED_STATE)
+	}
+
+	want := [][2]ConnectionState{
+		{CONNECTED_STATE, DISCONNECTED_STATE},
+		{DISCONNECTED_STATE, CONNECTED_STATE},
+		{CONNECTED_STATE, DISCONNECTED_STATE},
+	}
+	time.Sleep(10 * time.Millisecond)
+	got := recorder.got()
+	if len(got) != len(want) {
+		t.Fatalf("callback transitions = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("callback transition %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func Test_manager_StateCallbacks_Reentrant(t *testing.T) {
+	m, driver := newFakeManager()
+
+	recorder := newStateRecorder()
+	m.OnConnectionStateChange(func(previous ConnectionState, current ConnectionState) {
+		// Reacting to a disconnection by checking again, then closing the manager, must not deadlock
+		if current == DISCONNECTED_STATE && previous == CONNECTED_STATE {
+			driver.setConnectErr(nil)
+			m.IsConnected()
+			_ = m.Close()
+		}
+	})
+	m.OnConnectionStateChange(recorder.callback)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		driver.setConnectErr(errors.New("A typical error"))
+		m.IsConnected()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("a callback checking the connectivity or closing the manager deadlocked")
+	}
+
+	want := [][2]ConnectionState{
+		{CONNECTED_STATE, DISCONNECTED_STATE},
+		{DISCONNECTED_STATE, CONNECTED_STATE},
+		{CONNECTED_STATE, DISCONNECTED_STATE},
+	}
+	got := recorder.got()
+	if len(got) != len(want) {
+		t.Fatalf("callback transitions = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("callback transition %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
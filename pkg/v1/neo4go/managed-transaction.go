@@ -0,0 +1,177 @@
+package neo4go
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
+	internalMain "github.com/UlysseGuyon/neo4go/internal/neo4go"
+)
+
+// TransactionWork represents a unit of work executed inside a managed transaction.
+// Returning an error rolls back the transaction, returning nil commits it
+type TransactionWork func(Tx) error
+
+// RetryOptions represents the configuration of the retries applied to managed transactions.
+// Any zero value field will be replaced by its default value
+type RetryOptions struct {
+	// The maximum number of retries after the first attempt. A negative value disables retries
+	MaxRetries int
+
+	// The delay to wait before the first retry
+	InitialDelay time.Duration
+
+	// The maximum delay to wait between two retries
+	MaxDelay time.Duration
+
+	// The factor applied to the delay after each retry
+	Multiplier float64
+
+	// The ratio of each delay that is randomly added or removed to it, between 0 and 1
+	Jitter float64
+
+	// Tells if the delays are used without jitter, as a zero Jitter is replaced by its default value
+	NoJitter bool
+}
+
+// withDefaults returns a copy of the retry options in which every zero value field is set to its default value
+func (opt RetryOptions) withDefaults() RetryOptions {
+	if opt.MaxRetries == 0 {
+		opt.MaxRetries = internalMain.DefaultRetryMaxRetries
+	}
+	if opt.InitialDelay <= 0 {
+		opt.InitialDelay = internalMain.DefaultRetryInitialDelay
+	}
+	if opt.MaxDelay <= 0 {
+		opt.MaxDelay = internalMain.DefaultRetryMaxDelay
+	}
+	if opt.Multiplier <= 0 {
+		opt.Multiplier = internalMain.DefaultRetryMultiplier
+	}
+	if opt.NoJitter {
+		opt.Jitter = 0
+	} else if opt.Jitter <= 0 {
+		opt.Jitter = internalMain.DefaultRetryJitter
+	}
+	if opt.Jitter > 1 {
+		opt.Jitter = 1
+	}
+
+	return opt
+}
+
+// delay returns the time to wait before the given retry (starting at 0), with its jitter applied
+func (opt RetryOptions) delay(retry int) time.Duration {
+	baseDelay := float64(opt.InitialDelay)
+	for i := 0; i < retry && baseDelay < float64(opt.MaxDelay); i++ {
+		baseDelay *= opt.Multiplier
+	}
+	if baseDelay > float64(opt.MaxDelay) {
+		baseDelay = float64(opt.MaxDelay)
+	}
+
+	// The jitter is a random value in [-Jitter, +Jitter] times the delay
+	jitter := baseDelay * opt.Jitter * (2*rand.Float64() - 1)
+	return time.Duration(baseDelay + jitter)
+}
+
+// ReadTransaction runs the work inside a new read transaction, and retries it on temporary failures
+func (m *manager) ReadTransaction(params TransactionParams, work TransactionWork) Neo4GoError {
+	return m.ReadTransactionContext(context.Background(), params, work)
+}
+
+// ReadTransactionContext is the same as ReadTransaction, but stops retrying and rolls back the transaction if the context is done
+func (m *manager) ReadTransactionContext(ctx context.Context, params TransactionParams, work TransactionWork) Neo4GoError {
+	params.IsWrite = false
+	return m.runManagedTransaction(ctx, params, work)
+}
+
+// WriteTransaction runs the work inside a new write transaction, and retries it on temporary failures
+func (m *manager) WriteTransaction(params TransactionParams, work TransactionWork) Neo4GoError {
+	return m.WriteTransactionContext(context.Background(), params, work)
+}
+
+// WriteTransactionContext is the same as WriteTransaction, but stops retrying and rolls back the transaction if the context is done
+func (m *manager) WriteTransactionContext(ctx context.Context, params TransactionParams, work TransactionWork) Neo4GoError {
+	params.IsWrite = true
+	return m.runManagedTransaction(ctx, params, work)
+}
+
+// runManagedTransaction runs the work inside a new transaction until it succeeds, fails with a non retryable error,
+// or runs out of retries
+func (m *manager) runManagedTransaction(ctx context.Context, params TransactionParams, work TransactionWork) Neo4GoError {
+	return m.options.Retry.withDefaults().run(ctx, func() Neo4GoError {
+		return m.runManagedTransactionOnce(ctx, params, work)
+	})
+}
+
+// run calls the attempt until it succeeds, fails with a non retryable error, or runs out of retries.
+// The retry options must already have their default values set
+func (opt RetryOptions) run(ctx context.Context, attempt func() Neo4GoError) Neo4GoError {
+	for retry := 0; ; retry++ {
+		err := attempt()
+		if err == nil || !IsRetryableError(err) || retry >= opt.MaxRetries {
+			return err
+		}
+
+		// Wait before the next attempt, unless the context ends first
+		timer := time.NewTimer(opt.delay(retry))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return toContextError(ctx.Err())
+		}
+	}
+}
+
+// runManagedTransactionOnce runs the work inside a new transaction, then commits it on success or rolls it back on error or panic
+func (m *manager) runManagedTransactionOnce(ctx context.Context, params TransactionParams, work TransactionWork) Neo4GoError {
+	// A shutdown waits for the whole work, not only for its queries
+	if err := m.startOperation(true); err != nil {
+		return err
+	}
+	defer m.endOperation()
+
+	txID, err := m.BeginTransactionContext(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	// Roll back the transaction before propagating a panic of the work
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			_ = m.Rollback(txID)
+			panic(recovered)
+		}
+	}()
+
+	tx := newManagerTx(ctx, m, txID)
+	tx.managed = true
+
+	workErr := work(tx)
+	if workErr != nil {
+		// The transaction may have already been released if the error comes from an aborted query
+		_ = m.Rollback(txID)
+
+		// The errors of the queries are kept as they are, so that the temporary ones are retried
+		if neo4goErr, isNeo4goErr := workErr.(Neo4GoError); isNeo4goErr {
+			return neo4goErr
+		}
+
+		return &internalErr.TransactionError{
+			Err:   fmt.Sprintf("The work of the managed transaction returned an error : %s", workErr),
+			Cause: workErr,
+		}
+	}
+
+	commitErr := m.CommitContext(ctx, txID)
+	if commitErr != nil && !IsContextError(commitErr) {
+		// A failed commit leaves the transaction in the manager store, so it must still be released
+		_ = m.Rollback(txID)
+	}
+
+	return commitErr
+}
@@ -0,0 +1,172 @@
+package neo4go
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
+	internalMain "github.com/UlysseGuyon/neo4go/internal/neo4go"
+)
+
+func TestRetryOptions_withDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  RetryOptions
+		want RetryOptions
+	}{
+		{
+			name: "Should fill every zero value field",
+			opt:  RetryOptions{},
+			want: RetryOptions{
+				MaxRetries:   internalMain.DefaultRetryMaxRetries,
+				InitialDelay: internalMain.DefaultRetryInitialDelay,
+				MaxDelay:     internalMain.DefaultRetryMaxDelay,
+				Multiplier:   internalMain.DefaultRetryMultiplier,
+				Jitter:       internalMain.DefaultRetryJitter,
+			},
+		},
+		{
+			name: "Should keep the configured fields",
+			opt: RetryOptions{
+				MaxRetries:   -1,
+				InitialDelay: time.Millisecond,
+				MaxDelay:     time.Second,
+				Multiplier:   3,
+				Jitter:       0.5,
+			},
+			want: RetryOptions{
+				MaxRetries:   -1,
+				InitialDelay: time.Millisecond,
+				MaxDelay:     time.Second,
+				Multiplier:   3,
+				Jitter:       0.5,
+			},
+		},
+		{
+			name: "Should limit the jitter to 1",
+			opt:  RetryOptions{Jitter: 2},
+			want: RetryOptions{
+				MaxRetries:   internalMain.DefaultRetryMaxRetries,
+				InitialDelay: internalMain.DefaultRetryInitialDelay,
+				MaxDelay:     internalMain.DefaultRetryMaxDelay,
+				Multiplier:   internalMain.DefaultRetryMultiplier,
+				Jitter:       1,
+			},
+		},
+		{
+			name: "Should disable the jitter",
+			opt:  RetryOptions{Jitter: 0.5, NoJitter: true},
+			want: RetryOptions{
+				MaxRetries:   internalMain.DefaultRetryMaxRetries,
+				InitialDelay: internalMain.DefaultRetryInitialDelay,
+				MaxDelay:     internalMain.DefaultRetryMaxDelay,
+				Multiplier:   internalMain.DefaultRetryMultiplier,
+				Jitter:       0,
+				NoJitter:     true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opt.withDefaults(); got != tt.want {
+				t.Errorf("RetryOptions.withDefaults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryOptions_delay(t *testing.T) {
+	opt := RetryOptions{
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     time.Second,
+		Multiplier:   2,
+		Jitter:       0.1,
+	}.withDefaults()
+
+	tests := []struct {
+		name    string
+		retry   int
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "Should wait the initial delay before the first retry",
+			retry:   0,
+			wantMin: 90 * time.Millisecond,
+			wantMax: 110 * time.Millisecond,
+		},
+		{
+			name:    "Should multiply the delay after each retry",
+			retry:   2,
+			wantMin: 360 * time.Millisecond,
+			wantMax: 440 * time.Millisecond,
+		},
+		{
+			name:    "Should not wait more than the maximum delay",
+			retry:   10,
+			wantMin: 900 * time.Millisecond,
+			wantMax: 1100 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := opt.delay(tt.retry); got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("RetryOptions.delay() = %v, want between %v and %v", got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+var errWorkFailed = errors.New("work failed")
+
+func Test_manager_WriteTransaction_WorkError(t *testing.T) {
+	tests := []struct {
+		name      string
+		workErr   error
+		wantErr   error
+		wantTxErr bool
+		wantTrans bool
+		wantTries int
+	}{
+		{
+			name:      "Should wrap an error of the work without hiding it",
+			workErr:   errWorkFailed,
+			wantErr:   errWorkFailed,
+			wantTxErr: true,
+			wantTries: 1,
+		},
+		{
+			name:      "Should keep a neo4go error of the work as it is, and retry it if it is temporary",
+			workErr:   &internalErr.TransientError{Err: "A typical error"},
+			wantTrans: true,
+			wantTries: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, driver := newFakeManager()
+			m.options.Retry = RetryOptions{MaxRetries: 1, InitialDelay: time.Millisecond, NoJitter: true}
+
+			tries := 0
+			err := m.WriteTransaction(TransactionParams{}, func(tx Tx) error {
+				tries++
+				return tt.workErr
+			})
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("manager.WriteTransaction() error = %v, want an error wrapping %v", err, tt.wantErr)
+			}
+			if IsTransactionError(err) != tt.wantTxErr || IsTransientError(err) != tt.wantTrans || IsUnknownError(err) {
+				t.Errorf("manager.WriteTransaction() error = %v of type %T", err, err)
+			}
+			if tries != tt.wantTries {
+				t.Errorf("manager.WriteTransaction() ran the work %d times, want %d", tries, tt.wantTries)
+			}
+			for _, session := range driver.sessions {
+				if !session.transactions[0].rolledBack || !session.closed {
+					t.Errorf("manager.WriteTransaction() did not roll back the transaction of the failed work")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package neo4go
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
+)
+
+// driverFactory represents a function that creates the driver of a manager for the given URI
+type driverFactory func(uri string) (Driver, Neo4GoError)
+
+// newManagerWithDriverFactory creates a new instance of Manager on the first URI of the config that can be reached.
+// The factory is kept to switch to another URI when the current one cannot be reached anymore
+func newManagerWithDriverFactory(options ManagerOptions, factory driverFactory) (Manager, Neo4GoError) {
+	endpoints := managerEndpoints(options)
+
+	newDriver, endpointIndex, failures := connectEndpoint(factory, endpoints, 0, len(endpoints))
+	if newDriver == nil {
+		return nil, &internalErr.InitError{
+			Err:    fmt.Sprintf("Could not connect to any database URI : [%s]", strings.Join(failures, " / ")),
+			URI:    strings.Join(endpoints, ", "),
+			DBName: options.DatabaseName,
+		}
+	}
+
+	// The driver was just reached, so the manager starts connected
+	newManager := manager{state: CONNECTED_STATE}
+	err := newManager.init(options, newDriver)
+	if err != nil {
+		_ = newDriver.Close()
+		return nil, err
+	}
+
+	newManager.driverFactory = factory
+	newManager.endpoints = endpoints
+	newManager.endpointIndex = endpointIndex
+
+	return &newManager, nil
+}
+
+// managerEndpoints returns the URIs of the manager config, in the order they must be tried
+func managerEndpoints(options ManagerOptions) []string {
+	endpoints := make([]string, 0, len(options.URIs)+1)
+	if options.URI != "" {
+		endpoints = append(endpoints, options.URI)
+	}
+
+	return append(endpoints, options.URIs...)
+}
+
+// connectEndpoint creates the drivers of at most count URIs, starting at the given index and wrapping around,
+// until one of them can be reached. It returns the driver and the index of its URI, or nil and the failure of every URI
+func connectEndpoint(factory driverFactory, endpoints []string, start int, count int) (Driver, int, []string) {
+	failures := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		index := (start + i) % len(endpoints)
+
+		newDriver, err := factory(endpoints[index])
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s : %s", endpoints[index], err))
+			continue
+		}
+
+		if connectErr := newDriver.VerifyConnectivity(); connectErr != nil {
+			_ = newDriver.Close()
+			failures = append(failures, fmt.Sprintf("%s : %s", endpoints[index], connectErr))
+			continue
+		}
+
+		return newDriver, index, nil
+	}
+
+	return nil, -1, failures
+}
+
+// currentDriver returns the driver currently used by the manager
+func (m *manager) currentDriver() Driver {
+	m.driverMutex.RLock()
+	defer m.driverMutex.RUnlock()
+
+	return m.driver
+}
+
+// failoverOnError switches the manager to the next URI that can be reached if the error tells that the database is unavailable,
+// and if the driver that failed still cannot reach it. Nothing is done if the driver was given to the manager.
+// The switch is done in background, so that the failing caller does not wait for every other URI to be reached
+func (m *manager) failoverOnError(err Neo4GoError, failedDriver Driver) {
+	if !IsServiceUnavailableError(err) || m.driverFactory == nil || len(m.endpoints) < 2 {
+		return
+	}
+
+	// A shutdown waits for the switch, so that the manager is not released while a new driver is created
+	_ = m.startOperation(false)
+	go func() {
+		defer m.endOperation()
+		m.failover(failedDriver)
+	}()
+}
+
+// failover switches the manager from the failed driver to the next URI that can be reached, then closes the failed driver
+func (m *manager) failover(failedDriver Driver) {
+	// Only one goroutine switches the driver, the others see that the failed driver was already replaced
+	m.failoverMutex.Lock()
+	defer m.failoverMutex.Unlock()
+
+	if m.currentDriver() != failedDriver || failedDriver.VerifyConnectivity() == nil {
+		return
+	}
+
+	newDriver, endpointIndex, failures := connectEndpoint(m.driverFactory, m.endpoints, m.endpointIndex+1, len(m.endpoints)-1)
+	if newDriver == nil {
+		log.Printf("Could not switch to another database URI : [%s]\n", strings.Join(failures, " / "))
+		return
+	}
+
+	m.driverMutex.Lock()
+	if m.driverReleased {
+		// The manager was closed meanwhile
+		m.driverMutex.Unlock()
+		_ = newDriver.Close()
+		return
+	}
+	m.driver = newDriver
+	previousURI := m.endpoints[m.endpointIndex]
+	m.endpointIndex = endpointIndex
+	m.driverMutex.Unlock()
+
+	// The transactions still open on the failed driver cannot be used anymore
+	m.releaseDriverTransactions(failedDriver)
+	_ = failedDriver.Close()
+
+	if m.options.OnFailover != nil {
+		m.options.OnFailover(previousURI, m.endpoints[endpointIndex])
+	} else {
+		log.Printf("Switched from database URI %s to %s\n", previousURI, m.endpoints[endpointIndex])
+	}
+}
+
+// releaseDriverTransactions removes from the store every transaction opened by the given driver, then releases them,
+// so that they are not used after the driver is closed. The ones running a query are released in background once it returns
+func (m *manager) releaseDriverTransactions(usedDriver Driver) {
+	released := make([]*transactionSession, 0)
+	busy := make([]bool, 0)
+
+	m.transactionSessionsMutex.Lock()
+	for txID, txSession := range m.transactionSessions {
+		if txSession.driver != usedDriver {
+			continue
+		}
+
+		released = append(released, txSession)
+		busy = append(busy, txSession.runningQueries > 0 || txSession.ending)
+		delete(m.transactionSessions, txID)
+	}
+	m.transactionSessionsMutex.Unlock()
+
+	for i, txSession := range released {
+		if busy[i] {
+			go txSession.release()
+		} else {
+			_ = txSession.release()
+		}
+	}
+}
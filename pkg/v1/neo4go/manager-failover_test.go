@@ -0,0 +1,247 @@
+package neo4go
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
+)
+
+// newFakeDriverFactory returns a driver factory that creates the given fake drivers from their URIs
+func newFakeDriverFactory(drivers map[string]*fakeDriver) driverFactory {
+	return func(uri string) (Driver, Neo4GoError) {
+		driver, exists := drivers[uri]
+		if !exists {
+			return nil, &internalErr.InitError{Err: "Unknown URI", URI: uri}
+		}
+		return driver, nil
+	}
+}
+
+func Test_newManagerWithDriverFactory(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     ManagerOptions
+		unreachable []string
+		wantURI     string
+		wantErr     bool
+	}{
+		{
+			name:    "Should use the first URI",
+			options: ManagerOptions{URI: "bolt://primary:7687", URIs: []string{"bolt://standby:7687"}},
+			wantURI: "bolt://primary:7687",
+		},
+		{
+			name:        "Should use the first URI that can be reached",
+			options:     ManagerOptions{URI: "bolt://primary:7687", URIs: []string{"bolt://standby:7687"}},
+			unreachable: []string{"bolt://primary:7687"},
+			wantURI:     "bolt://standby:7687",
+		},
+		{
+			name:        "Should use the URIs without URI",
+			options:     ManagerOptions{URIs: []string{"bolt://primary:7687", "bolt://standby:7687"}},
+			unreachable: []string{"bolt://primary:7687"},
+			wantURI:     "bolt://standby:7687",
+		},
+		{
+			name:        "Should not create a manager when no URI can be reached",
+			options:     ManagerOptions{URI: "bolt://primary:7687", URIs: []string{"bolt://standby:7687"}},
+			unreachable: []string{"bolt://primary:7687", "bolt://standby:7687"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drivers := map[string]*fakeDriver{"bolt://primary:7687": {}, "bolt://standby:7687": {}}
+			for _, uri := range tt.unreachable {
+				drivers[uri].connectErr = errors.New("connection refused")
+			}
+
+			tt.options.DatabaseName = "neo4j"
+			got, err := newManagerWithDriverFactory(tt.options, newFakeDriverFactory(drivers))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newManagerWithDriverFactory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !IsInitError(err) {
+					t.Errorf("newManagerWithDriverFactory() error = %v, want an Init error", err)
+				}
+				return
+			}
+
+			m := got.(*manager)
+			if m.currentDriver() != drivers[tt.wantURI] {
+				t.Errorf("newManagerWithDriverFactory() did not use the driver of %s", tt.wantURI)
+			}
+			for _, uri := range tt.unreachable {
+				if !drivers[uri].closed {
+					t.Errorf("newManagerWithDriverFactory() did not close the driver of the unreachable %s", uri)
+				}
+			}
+		})
+	}
+}
+
+func Test_manager_failoverOnError(t *testing.T) {
+	tests := []struct {
+		name            string
+		primaryDown     bool
+		standbyDown     bool
+		wantFailover    bool
+		wantPrimaryUsed bool
+	}{
+		{
+			name:         "Should switch to the standby when the primary is down",
+			primaryDown:  true,
+			wantFailover: true,
+		},
+		{
+			name:            "Should keep the primary when it can still be reached",
+			wantPrimaryUsed: true,
+		},
+		{
+			name:            "Should keep the primary when the standby is down too",
+			primaryDown:     true,
+			standbyDown:     true,
+			wantPrimaryUsed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, standby := &fakeDriver{}, &fakeDriver{}
+			drivers := map[string]*fakeDriver{"bolt://primary:7687": primary, "bolt://standby:7687": standby}
+
+			var gotFailovers [][2]string
+			got, err := newManagerWithDriverFactory(ManagerOptions{
+				URI:          "bolt://primary:7687",
+				URIs:         []string{"bolt://standby:7687"},
+				DatabaseName: "neo4j",
+				OnFailover: func(previousURI string, newURI string) {
+					gotFailovers = append(gotFailovers, [2]string{previousURI, newURI})
+				},
+			}, newFakeDriverFactory(drivers))
+			if err != nil {
+				t.Fatalf("newManagerWithDriverFactory() error = %v", err)
+			}
+			m := got.(*manager)
+
+			// The primary goes down after the manager was created
+			primary.runErr = &internalErr.UnavailableError{Err: "connection reset"}
+			if tt.primaryDown {
+				primary.connectErr = errors.New("connection refused")
+			}
+			if tt.standbyDown {
+				standby.connectErr = errors.New("connection refused")
+			}
+
+			if _, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u"}); !IsServiceUnavailableError(err) {
+				t.Fatalf("manager.Query() error = %v, want an Unavailable error", err)
+			}
+			// The switch is done in background
+			for m.runningOperations() > 0 {
+				time.Sleep(time.Millisecond)
+			}
+
+			if tt.wantPrimaryUsed {
+				if m.currentDriver() != primary || len(gotFailovers) != 0 {
+					t.Errorf("manager switched to the standby, want the primary")
+				}
+				return
+			}
+
+			if len(gotFailovers) != 1 || gotFailovers[0] != [2]string{"bolt://primary:7687", "bolt://standby:7687"} {
+				t.Errorf("manager failovers = %v, want one from the primary to the standby", gotFailovers)
+			}
+			if !primary.closed {
+				t.Errorf("manager did not close the driver of the primary")
+			}
+			if _, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u"}); err != nil {
+				t.Errorf("manager.Query() after failover error = %v", err)
+			}
+			if len(standby.sessions) != 1 {
+				t.Errorf("manager.Query() after failover opened %d sessions on the standby, want 1", len(standby.sessions))
+			}
+		})
+	}
+}
+
+func Test_manager_failoverOnError_Concurrency(t *testing.T) {
+	primary, standby := &fakeDriver{}, &fakeDriver{}
+	drivers := map[string]*fakeDriver{"bolt://primary:7687": primary, "bolt://standby:7687": standby}
+
+	failovers := 0
+	got, err := newManagerWithDriverFactory(ManagerOptions{
+		URIs:         []string{"bolt://primary:7687", "bolt://standby:7687"},
+		DatabaseName: "neo4j",
+		OnFailover:   func(string, string) { failovers++ },
+	}, newFakeDriverFactory(drivers))
+	if err != nil {
+		t.Fatalf("newManagerWithDriverFactory() error = %v", err)
+	}
+	m := got.(*manager)
+	primary.connectErr = errors.New("connection refused")
+
+	// Every goroutine sees the failure of the primary, but it is only replaced once
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.failoverOnError(&internalErr.UnavailableError{Err: "connection reset"}, primary)
+		}()
+	}
+	wg.Wait()
+	for m.runningOperations() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if failovers != 1 || m.currentDriver() != standby {
+		t.Errorf("manager switched %d times, want once to the standby", failovers)
+	}
+}
+
+func Test_manager_failoverOnError_Transactions(t *testing.T) {
+	primary, standby := &fakeDriver{}, &fakeDriver{}
+	drivers := map[string]*fakeDriver{"bolt://primary:7687": primary, "bolt://standby:7687": standby}
+
+	got, err := newManagerWithDriverFactory(ManagerOptions{
+		URIs:         []string{"bolt://primary:7687", "bolt://standby:7687"},
+		DatabaseName: "neo4j",
+		OnFailover:   func(string, string) {},
+	}, newFakeDriverFactory(drivers))
+	if err != nil {
+		t.Fatalf("newManagerWithDriverFactory() error = %v", err)
+	}
+	m := got.(*manager)
+
+	txID, err := m.BeginTransaction(TransactionParams{IsWrite: true})
+	if err != nil {
+		t.Fatalf("manager.BeginTransaction() error = %v", err)
+	}
+
+	// The primary goes down while the transaction is open
+	primary.runErrs = []error{&internalErr.UnavailableError{Err: "connection reset"}}
+	primary.connectErr = errors.New("connection refused")
+	if _, err := m.Query(QueryParams{Query: "CREATE (u:User)", Transaction: txID}); !IsServiceUnavailableError(err) {
+		t.Fatalf("manager.Query() error = %v, want an Unavailable error", err)
+	}
+	for m.runningOperations() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The transaction of the primary is released before its driver is closed, and cannot be used anymore
+	if m.currentDriver() != standby || !primary.closed {
+		t.Fatalf("manager did not switch to the standby")
+	}
+	if !primary.sessions[0].isClosed() || !primary.sessions[0].transactions[0].rolledBack {
+		t.Errorf("manager did not release the transaction of the primary")
+	}
+	if _, err := m.Query(QueryParams{Query: "CREATE (u:User)", Transaction: txID}); !IsTransactionError(err) {
+		t.Errorf("manager.Query() after failover error = %v, want a Transaction error", err)
+	}
+	if err := m.Commit(txID); !IsTransactionError(err) {
+		t.Errorf("manager.Commit() after failover error = %v, want a Transaction error", err)
+	}
+}
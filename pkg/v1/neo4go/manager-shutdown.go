@@ -0,0 +1,218 @@
+package neo4go
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
+	internalMain "github.com/UlysseGuyon/neo4go/internal/neo4go"
+)
+
+// Close stops accepting new queries and transactions, waits for the running ones during the ShutdownTimeout of the options,
+// then rolls back every open transaction and closes every session and the driver. The returned Shutdown error lists every resource that could not be released
+func (m *manager) Close() Neo4GoError {
+	timeout := m.options.ShutdownTimeout
+	if timeout == 0 {
+		timeout = internalMain.DefaultShutdownTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return m.Shutdown(ctx)
+}
+
+// Shutdown stops accepting new queries and transactions, and waits for the running ones until the context is done.
+// Then, it releases everything like Close does. The queries and commits of the open transactions are still accepted while waiting,
+// and the transactions still in use once the context is done are released in background
+func (m *manager) Shutdown(ctx context.Context) Neo4GoError {
+	m.operationsMutex.Lock()
+	m.shuttingDown = true
+	var operationsDone chan struct{}
+	if m.operations > 0 {
+		if m.operationsDone == nil {
+			m.operationsDone = make(chan struct{})
+		}
+		operationsDone = m.operationsDone
+	}
+	m.operationsMutex.Unlock()
+
+	// A shutting down manager is disconnected right away, so that it does not receive traffic anymore
+	m.checkConnection()
+
+	// Wait for the running operations, unless the context ends first
+	var waitErr error
+	if operationsDone != nil {
+		select {
+		case <-operationsDone:
+		case <-ctx.Done():
+			m.operationsMutex.Lock()
+			waitErr = &internalErr.ContextError{
+				Err:   fmt.Sprintf("Stopped waiting for %d running operations : %s", m.operations, ctx.Err()),
+				Cause: ctx.Err(),
+			}
+			m.operationsMutex.Unlock()
+		}
+	}
+
+	releaseErr := m.release()
+	if waitErr == nil {
+		return releaseErr
+	}
+
+	// The operations that were given up on are reported along with the resources that could not be released
+	failures := []error{waitErr}
+	if shutdownErr, isShutdownErr := releaseErr.(*internalErr.ShutdownError); isShutdownErr {
+		failures = append(failures, shutdownErr.Failures...)
+	}
+
+	return &internalErr.ShutdownError{
+		Err:      "Could not shut down the manager cleanly",
+		Failures: failures,
+	}
+}
+
+// release rolls back every open transaction, then closes every open session and the driver.
+// Only the first call has an effect, the next ones return the same error
+func (m *manager) release() Neo4GoError {
+	m.releaseOnce.Do(func() {
+		failures := make([]error, 0)
+
+		// Take all the transactions out of the store, so that they cannot be used while they are released
+		m.transactionSessionsMutex.Lock()
+		if m.stopReaper != nil {
+			close(m.stopReaper)
+			m.stopReaper = nil
+		}
+		if m.stopHealthChecker != nil {
+			close(m.stopHealthChecker)
+			m.stopHealthChecker = nil
+		}
+		openTransactions := m.transactionSessions
+		m.transactionSessions = make(map[string]*transactionSession)
+		m.transactionsReleased = true
+		busyTransactions := make(map[string]bool)
+		for txID, txSession := range openTransactions {
+			busyTransactions[txID] = txSession.runningQueries > 0 || txSession.ending
+		}
+		m.transactionSessionsMutex.Unlock()
+
+		// Release the oldest transactions first, so that the failures are always listed in the same order
+		txIDs := make([]string, 0, len(openTransactions))
+		for txID := range openTransactions {
+			txIDs = append(txIDs, txID)
+		}
+		sort.Slice(txIDs, func(i, j int) bool {
+			return openTransactions[txIDs[i]].createdAt.Before(openTransactions[txIDs[j]].createdAt)
+		})
+		for _, txID := range txIDs {
+			// The driver cannot close a transaction while it runs a query, so a transaction still in use once the wait is over
+			// is released in background when its query returns, instead of blocking the shutdown past its deadline
+			if busyTransactions[txID] {
+				go openTransactions[txID].release()
+				failures = append(failures, &internalErr.TransactionError{
+					Err: fmt.Sprintf("Could not release the transaction %s as it is still in use, it is released in background once its query returns", txID),
+				})
+				continue
+			}
+
+			if err := openTransactions[txID].release(); err != nil {
+				failures = append(failures, &internalErr.TransactionError{
+					Err: fmt.Sprintf("Could not release the transaction %s : %s", txID, err),
+				})
+			}
+		}
+
+		m.operationsMutex.Lock()
+		openSessions := m.openSessions
+		m.openSessions = nil
+		m.operationsMutex.Unlock()
+
+		for session := range openSessions {
+			if err := session.Close(); err != nil {
+				failures = append(failures, toDriverError(err))
+			}
+		}
+
+		// No other driver can replace this one once it is closed
+		m.driverMutex.Lock()
+		m.driverReleased = true
+		usedDriver := m.driver
+		m.driverMutex.Unlock()
+
+		if err := usedDriver.Close(); err != nil {
+			failures = append(failures, toDriverError(err))
+		}
+
+		if len(failures) > 0 {
+			m.releaseErr = &internalErr.ShutdownError{
+				Err:      "Could not release every resource of the manager",
+				Failures: failures,
+			}
+		}
+	})
+
+	return m.releaseErr
+}
+
+// startOperation marks the start of a query, transaction, commit or rollback, which a shutdown waits for.
+// A new operation is refused once the manager is shutting down. Every accepted operation must be ended with endOperation
+func (m *manager) startOperation(isNew bool) Neo4GoError {
+	m.operationsMutex.Lock()
+	defer m.operationsMutex.Unlock()
+
+	if isNew && m.shuttingDown {
+		return shutdownError()
+	}
+
+	m.operations++
+	return nil
+}
+
+// endOperation marks the end of an operation accepted by startOperation
+func (m *manager) endOperation() {
+	m.operationsMutex.Lock()
+	defer m.operationsMutex.Unlock()
+
+	m.operations--
+	if m.operations == 0 && m.operationsDone != nil {
+		close(m.operationsDone)
+		m.operationsDone = nil
+	}
+}
+
+// trackSession stores the session of an auto-commit query until it is closed, so that a shutdown can close it
+func (m *manager) trackSession(session Session) Neo4GoError {
+	m.operationsMutex.Lock()
+	defer m.operationsMutex.Unlock()
+
+	if m.openSessions == nil {
+		return shutdownError()
+	}
+
+	m.openSessions[session] = struct{}{}
+	return nil
+}
+
+// closeSession closes the session of an auto-commit query and stops tracking it.
+// A session that is not tracked anymore was already closed by a shutdown, so it is not closed twice
+func (m *manager) closeSession(session Session) error {
+	m.operationsMutex.Lock()
+	_, isOpen := m.openSessions[session]
+	delete(m.openSessions, session)
+	m.operationsMutex.Unlock()
+
+	if !isOpen {
+		return nil
+	}
+
+	return session.Close()
+}
+
+// shutdownError returns the error given when a new query or transaction is started on a shutting down manager
+func shutdownError() Neo4GoError {
+	return &internalErr.ShutdownError{
+		Err: "Trying to use a manager that is shut down",
+	}
+}
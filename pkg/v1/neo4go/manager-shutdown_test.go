@@ -0,0 +1,256 @@
+package neo4go
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_manager_Close_Failures(t *testing.T) {
+	m, driver := newFakeManager()
+	driver.closeErr = errors.New("A typical error")
+
+	for i := 0; i < 2; i++ {
+		if _, err := m.BeginTransaction(TransactionParams{}); err != nil {
+			t.Fatalf("manager.BeginTransaction() error = %v", err)
+		}
+	}
+
+	err := m.Close()
+	if !IsShutdownError(err) {
+		t.Fatalf("manager.Close() error = %v, want a Shutdown error", err)
+	}
+	// Every failure is reported : the two transactions, then the driver
+	if failures := ShutdownErrorFailures(err); len(failures) != 3 {
+		t.Errorf("manager.Close() failures = %v, want 3 failures", failures)
+	}
+	if !driver.closed {
+		t.Errorf("manager.Close() did not close the driver after a failure")
+	}
+	for i, session := range driver.sessions {
+		if !session.closed || !session.transactions[0].rolledBack {
+			t.Errorf("manager.Close() did not release the transaction %d after a failure", i)
+		}
+	}
+	if again := m.Close(); again != err {
+		t.Errorf("manager.Close() called twice error = %v, want %v", again, err)
+	}
+}
+
+func Test_manager_Close_StreamedSession(t *testing.T) {
+	m, driver := newFakeManager()
+
+	if _, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u", ResultMode: STREAMED_RESULT}); err != nil {
+		t.Fatalf("manager.Query() error = %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("manager.Close() error = %v", err)
+	}
+	if !driver.sessions[0].closed {
+		t.Errorf("manager.Close() did not close the session of a streamed result")
+	}
+}
+
+func Test_manager_Shutdown(t *testing.T) {
+	tests := []struct {
+		name           string
+		timeout        time.Duration
+		wantCommitted  bool
+		wantRolledBack bool
+		wantErr        bool
+	}{
+		{
+			name:          "Should wait for the running transaction",
+			timeout:       time.Minute,
+			wantCommitted: true,
+		},
+		{
+			name:           "Should roll back the running transaction after the deadline",
+			timeout:        10 * time.Millisecond,
+			wantRolledBack: true,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, driver := newFakeManager()
+
+			workStarted := make(chan struct{})
+			resumeWork := make(chan struct{})
+			workDone := make(chan Neo4GoError)
+			go func() {
+				workDone <- m.WriteTransaction(TransactionParams{}, func(tx Tx) error {
+					close(workStarted)
+					<-resumeWork
+					_, err := tx.Query(QueryParams{Query: "CREATE (u:User)"})
+					return err
+				})
+			}()
+			<-workStarted
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			shutdownDone := make(chan Neo4GoError)
+			go func() {
+				shutdownDone <- m.Shutdown(ctx)
+			}()
+
+			// Wait until the shutdown has started, then check that new queries are refused meanwhile
+			for {
+				if _, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u"}); IsShutdownError(err) {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+			if _, err := m.BeginTransaction(TransactionParams{}); !IsShutdownError(err) {
+				t.Errorf("manager.BeginTransaction() while shutting down error = %v, want a Shutdown error", err)
+			}
+
+			var shutdownErr Neo4GoError
+			if tt.wantErr {
+				shutdownErr = <-shutdownDone
+				close(resumeWork)
+				<-workDone
+			} else {
+				close(resumeWork)
+				if err := <-workDone; err != nil {
+					t.Errorf("manager.WriteTransaction() while shutting down error = %v", err)
+				}
+				shutdownErr = <-shutdownDone
+			}
+
+			if (shutdownErr != nil) != tt.wantErr {
+				t.Errorf("manager.Shutdown() error = %v, wantErr %v", shutdownErr, tt.wantErr)
+			}
+			if tt.wantErr && !IsShutdownError(shutdownErr) {
+				t.Errorf("manager.Shutdown() error = %v, want a Shutdown error", shutdownErr)
+			}
+
+			fakeTx := driver.sessions[0].transactions[0]
+			if fakeTx.committed != tt.wantCommitted || fakeTx.rolledBack != tt.wantRolledBack {
+				t.Errorf("transaction committed = %v / rolled back = %v, want %v / %v", fakeTx.committed, fakeTx.rolledBack, tt.wantCommitted, tt.wantRolledBack)
+			}
+			if !driver.closed {
+				t.Errorf("manager.Shutdown() did not close the driver")
+			}
+		})
+	}
+}
+
+func Test_manager_Shutdown_StuckQuery(t *testing.T) {
+	m, driver := newFakeManager()
+
+	txID, err := m.BeginTransaction(TransactionParams{IsWrite: true})
+	if err != nil {
+		t.Fatalf("manager.BeginTransaction() error = %v", err)
+	}
+	session := driver.sessions[0]
+	fakeTx := session.transactions[0]
+	fakeTx.runBlock = make(chan struct{})
+
+	queryDone := make(chan Neo4GoError)
+	go func() {
+		_, err := m.Query(QueryParams{Query: "CREATE (u:User)", Transaction: txID})
+		queryDone <- err
+	}()
+	for m.runningOperations() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The query is stuck in the driver, so the shutdown must give up on its transaction once its deadline is over
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	shutdownDone := make(chan Neo4GoError)
+	go func() {
+		shutdownDone <- m.Shutdown(ctx)
+	}()
+
+	var shutdownErr Neo4GoError
+	select {
+	case shutdownErr = <-shutdownDone:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("manager.Shutdown() was still blocked by the stuck query after its deadline")
+	}
+	if !IsShutdownError(shutdownErr) {
+		t.Fatalf("manager.Shutdown() error = %v, want a Shutdown error", shutdownErr)
+	}
+	// The wait and the transaction released in background are both reported
+	failures := ShutdownErrorFailures(shutdownErr)
+	if len(failures) != 2 || !IsContextError(failures[0]) || !IsTransactionError(failures[1]) {
+		t.Errorf("manager.Shutdown() failures = %v, want a Context error and a Transaction error", failures)
+	}
+	if !driver.closed {
+		t.Errorf("manager.Shutdown() did not close the driver")
+	}
+
+	// Once its query returns, the transaction is rolled back and its session closed
+	close(fakeTx.runBlock)
+	<-queryDone
+	for !session.isClosed() {
+		time.Sleep(time.Millisecond)
+	}
+	if fakeTx.committed || !fakeTx.rolledBack {
+		t.Errorf("stuck transaction committed = %v / rolled back = %v, want false / true", fakeTx.committed, fakeTx.rolledBack)
+	}
+}
+
+func Test_manager_Close_WaitsForRunningQueries(t *testing.T) {
+	m, driver := newFakeManager()
+	m.options.ShutdownTimeout = time.Minute
+
+	workStarted := make(chan struct{})
+	resumeWork := make(chan struct{})
+	workDone := make(chan Neo4GoError)
+	go func() {
+		workDone <- m.WriteTransaction(TransactionParams{}, func(tx Tx) error {
+			close(workStarted)
+			<-resumeWork
+			_, err := tx.Query(QueryParams{Query: "CREATE (u:User)"})
+			return err
+		})
+	}()
+	<-workStarted
+
+	closeDone := make(chan Neo4GoError)
+	go func() {
+		closeDone <- m.Close()
+	}()
+
+	// Wait until the close has started before letting the work end
+	for {
+		if _, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u"}); IsShutdownError(err) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(resumeWork)
+
+	if err := <-workDone; err != nil {
+		t.Errorf("manager.WriteTransaction() while closing error = %v", err)
+	}
+	if err := <-closeDone; err != nil {
+		t.Errorf("manager.Close() error = %v", err)
+	}
+	if fakeTx := driver.sessions[0].transactions[0]; !fakeTx.committed {
+		t.Errorf("manager.Close() did not wait for the running transaction to commit")
+	}
+}
+
+func Test_manager_Close_SessionsClosedOnce(t *testing.T) {
+	m, driver := newFakeManager()
+
+	result, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u", ResultMode: STREAMED_RESULT})
+	if err != nil {
+		t.Fatalf("manager.Query() error = %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("manager.Close() error = %v", err)
+	}
+	if err := result.Close(); err != nil {
+		t.Errorf("QueryResult.Close() after manager.Close() error = %v", err)
+	}
+	if closeCount := driver.sessions[0].closeCount; closeCount != 1 {
+		t.Errorf("session of the streamed result closed %d times, want 1", closeCount)
+	}
+}
@@ -1,14 +1,45 @@
 package neo4go
 
 import (
-	"strings"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
 
 	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
+	"github.com/neo4j/neo4j-go-driver/neo4j"
 )
 
+// databaseName returns the database used by a query or transaction, which is the database of the manager unless another one is given
+func (m *manager) databaseName(databaseName string) string {
+	if databaseName != "" {
+		return databaseName
+	}
+
+	return m.options.DatabaseName
+}
+
+// sessionAccessMode returns the access mode of the session of an auto-commit query.
+// The access mode asked by the caller takes precedence over the one detected from the query
+func sessionAccessMode(accessMode AccessMode, tokens []cypherToken) neo4j.AccessMode {
+	switch accessMode {
+	case READ_ACCESS_MODE:
+		return neo4j.AccessModeRead
+	case WRITE_ACCESS_MODE:
+		return neo4j.AccessModeWrite
+	}
+
+	if _, isWrite := classifyCypherTokens(tokens); isWrite {
+		return neo4j.AccessModeWrite
+	}
+
+	return neo4j.AccessModeRead
+}
+
 // validateManagerOptions allows early detection of wrong options
 func validateManagerOptions(opt ManagerOptions) Neo4GoError {
-	if opt.URI == "" {
+	if opt.URI == "" && len(opt.URIs) == 0 {
 		return &internalErr.InitError{
 			Err:    "Database URI given in options is empty",
 			DBName: opt.DatabaseName,
@@ -16,6 +47,16 @@ func validateManagerOptions(opt ManagerOptions) Neo4GoError {
 		}
 	}
 
+	for _, uri := range opt.URIs {
+		if uri == "" {
+			return &internalErr.InitError{
+				Err:    "One of the database URIs given in options is empty",
+				DBName: opt.DatabaseName,
+				URI:    opt.URI,
+			}
+		}
+	}
+
 	if opt.DatabaseName == "" {
 		return &internalErr.InitError{
 			Err:    "Database name given in options is empty",
@@ -24,33 +65,187 @@ func validateManagerOptions(opt ManagerOptions) Neo4GoError {
 		}
 	}
 
+	if err := validateTransactionConfig(opt.DefaultTimeout, opt.DefaultMetadata); err != nil {
+		return &internalErr.InitError{
+			Err:    fmt.Sprintf("Default transaction config given in options is wrong : %s", err),
+			DBName: opt.DatabaseName,
+			URI:    opt.URI,
+		}
+	}
+
 	return nil
 }
 
-// IsWriteQuery determines if the given cypher query's type is write or read
-func IsWriteQuery(query string) bool {
-	isWrite := false
+// queryParameters returns the names of all the parameters used by a tokenized query, in order of first appearance
+func queryParameters(tokens []cypherToken) []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, token := range tokens {
+		if token.kind == cypherParameter && !seen[token.text] {
+			seen[token.text] = true
+			names = append(names, token.text)
+		}
+	}
 
-	allWriteClauses := []string{
-		"CREATE",
-		"MERGE",
-		"DELETE",
-		"SET",
-		"REMOVE",
-		"FOREACH",
-		"DROP",
-		"ALTER",
-		"RENAME",
-		"GRANT",
-		"REVOKE",
-		"DENY",
+	return names
+}
+
+// validateQueryParams checks that every parameter used by a tokenized query is given in its params.
+// It returns the names of the given params that are not used by the query
+func validateQueryParams(tokens []cypherToken, params map[string]InputStruct) ([]string, Neo4GoError) {
+	usedNames := queryParameters(tokens)
+
+	missing := make([]string, 0)
+	used := make(map[string]bool, len(usedNames))
+	for _, name := range usedNames {
+		used[name] = true
+		if _, exists := params[name]; !exists {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &internalErr.ParameterError{
+			Err:     "The query uses parameters that are not given",
+			Missing: missing,
+		}
 	}
 
-	for _, writeClause := range allWriteClauses {
-		if strings.Contains(strings.ToLower(query), strings.ToLower(writeClause)) {
-			isWrite = true
+	unused := make([]string, 0)
+	for name := range params {
+		if !used[name] {
+			unused = append(unused, name)
 		}
 	}
+	sort.Strings(unused)
+
+	return unused, nil
+}
 
+// IsWriteQuery determines if the given cypher query must run in a write session.
+// Write, schema and administration queries do, as well as the calls of the procedures that are known to write
+func IsWriteQuery(query string) bool {
+	_, isWrite := classifyCypherTokens(tokenizeCypher(query))
 	return isWrite
 }
+
+// runWithContext runs a blocking work and returns early with a Context error if the context is done before the work ends.
+// In that case, onAbandon is called in background once the work eventually ends, so that its resources can be released.
+// The abandoned work still counts as a running operation of the manager until onAbandon returns, so that a shutdown waits for it.
+// If the context is already done, the work is skipped and onAbandon is called right away
+func (m *manager) runWithContext(ctx context.Context, work func() Neo4GoError, onAbandon func()) Neo4GoError {
+	if ctx.Err() != nil {
+		if onAbandon != nil {
+			onAbandon()
+		}
+		return toContextError(ctx.Err())
+	}
+
+	done := make(chan Neo4GoError, 1)
+	go func() {
+		done <- work()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		// The operation of the caller ends when it returns, so the abandoned work is counted as an operation of its own
+		_ = m.startOperation(false)
+		go func() {
+			defer m.endOperation()
+
+			<-done
+			if onAbandon != nil {
+				onAbandon()
+			}
+		}()
+		return toContextError(ctx.Err())
+	}
+}
+
+// validateTransactionConfig allows early detection of a wrong timeout or metadata of a transaction or query
+func validateTransactionConfig(timeout time.Duration, metadata map[string]InputStruct) error {
+	// The database counts timeouts in milliseconds, so a shorter timeout would disable it
+	if timeout < 0 || (timeout > 0 && timeout < time.Millisecond) {
+		return fmt.Errorf("Timeout %s must be zero or at least one millisecond", timeout)
+	}
+
+	for key, value := range metadata {
+		if key == "" {
+			return errors.New("Metadata keys cannot be empty")
+		}
+		if value == nil {
+			return fmt.Errorf("Metadata value of key %s is nil", key)
+		}
+	}
+
+	return nil
+}
+
+// transactionConfigurers returns the configurers of a transaction or auto-commit query.
+// The timeout and the metadata, over the defaults of the manager, are applied first so that the raw configurers can override them
+func (m *manager) transactionConfigurers(timeout time.Duration, metadata map[string]InputStruct, configurers []func(*neo4j.TransactionConfig)) []func(*neo4j.TransactionConfig) {
+	usedTimeout := m.options.DefaultTimeout
+	if timeout > 0 {
+		usedTimeout = timeout
+	}
+
+	usedMetadata := make(map[string]interface{}, len(m.options.DefaultMetadata)+len(metadata))
+	for key, value := range m.options.DefaultMetadata {
+		usedMetadata[key] = convertInputObject(value)
+	}
+	for key, value := range metadata {
+		usedMetadata[key] = convertInputObject(value)
+	}
+
+	if usedTimeout <= 0 && len(usedMetadata) == 0 {
+		return configurers
+	}
+
+	usedConfigurers := make([]func(*neo4j.TransactionConfig), 0, len(configurers)+1)
+	usedConfigurers = append(usedConfigurers, func(config *neo4j.TransactionConfig) {
+		if usedTimeout > 0 {
+			config.Timeout = usedTimeout
+		}
+		if len(usedMetadata) > 0 {
+			config.Metadata = usedMetadata
+		}
+	})
+	usedConfigurers = append(usedConfigurers, configurers...)
+
+	return usedConfigurers
+}
+
+// withContextTimeout adds a transaction timeout to the given configurers if the context has a deadline,
+// so that the database itself stops the transaction when the context expires
+func withContextTimeout(ctx context.Context, configurers []func(*neo4j.TransactionConfig)) []func(*neo4j.TransactionConfig) {
+	deadline, hasDeadline := ctx.Deadline()
+	if !hasDeadline {
+		return configurers
+	}
+
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		remaining = time.Millisecond
+	}
+
+	usedConfigurers := make([]func(*neo4j.TransactionConfig), 0, len(configurers)+1)
+	usedConfigurers = append(usedConfigurers, configurers...)
+
+	// NOTE This one must be last so that it only shortens a timeout that was already configured
+	usedConfigurers = append(usedConfigurers, func(config *neo4j.TransactionConfig) {
+		if config.Timeout <= 0 || config.Timeout > remaining {
+			config.Timeout = remaining
+		}
+	})
+
+	return usedConfigurers
+}
+
+// toContextError converts the error of a done context into a neo4go Context error
+func toContextError(err error) Neo4GoError {
+	return &internalErr.ContextError{
+		Err:   err.Error(),
+		Cause: err,
+	}
+}
@@ -1,7 +1,14 @@
 package neo4go
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"testing"
+	"time"
+
+	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
+	"github.com/neo4j/neo4j-go-driver/neo4j"
 )
 
 func Test_validateManagerOptions(t *testing.T) {
@@ -32,6 +39,38 @@ func Test_validateManagerOptions(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "Should allow options with URIs instead of URI",
+			args: args{
+				opt: ManagerOptions{
+					URIs:         []string{"bolt://primary:7687", "bolt://standby:7687"},
+					DatabaseName: "neo4j",
+				},
+			},
+			want: false,
+		},
+		{
+			name: "Should not allow options with an empty URI in URIs",
+			args: args{
+				opt: ManagerOptions{
+					URI:          "bolt://localhost:7687",
+					URIs:         []string{""},
+					DatabaseName: "neo4j",
+				},
+			},
+			want: true,
+		},
+		{
+			name: "Should not allow options with a negative default timeout",
+			args: args{
+				opt: ManagerOptions{
+					URI:            "bolt://localhost:7687",
+					DatabaseName:   "neo4j",
+					DefaultTimeout: -time.Second,
+				},
+			},
+			want: true,
+		},
 		{
 			name: "Should not allow options without URI",
 			args: args{
@@ -134,3 +173,377 @@ func Test_isWriteQuery(t *testing.T) {
 		})
 	}
 }
+
+func Test_sessionAccessMode(t *testing.T) {
+	type args struct {
+		accessMode AccessMode
+		query      string
+	}
+	tests := []struct {
+		name string
+		args args
+		want neo4j.AccessMode
+	}{
+		{
+			name: "Should detect read access mode",
+			args: args{accessMode: AUTO_ACCESS_MODE, query: "MATCH (u:User) RETURN u"},
+			want: neo4j.AccessModeRead,
+		},
+		{
+			name: "Should detect write access mode",
+			args: args{accessMode: AUTO_ACCESS_MODE, query: "CALL db.createLabel('User')"},
+			want: neo4j.AccessModeWrite,
+		},
+		{
+			name: "Should detect read access mode on a procedure call",
+			args: args{accessMode: AUTO_ACCESS_MODE, query: "CALL db.awaitIndexes()"},
+			want: neo4j.AccessModeRead,
+		},
+		{
+			name: "Should force read access mode on a write procedure call",
+			args: args{accessMode: READ_ACCESS_MODE, query: "CALL db.createLabel('User')"},
+			want: neo4j.AccessModeRead,
+		},
+		{
+			name: "Should force write access mode on a procedure call",
+			args: args{accessMode: WRITE_ACCESS_MODE, query: "CALL myapp.importUsers()"},
+			want: neo4j.AccessModeWrite,
+		},
+		{
+			name: "Should force write access mode on a read query",
+			args: args{accessMode: WRITE_ACCESS_MODE, query: "MATCH (u:User) RETURN u"},
+			want: neo4j.AccessModeWrite,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sessionAccessMode(tt.args.accessMode, tokenizeCypher(tt.args.query)); got != tt.want {
+				t.Errorf("sessionAccessMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validateQueryParams(t *testing.T) {
+	type args struct {
+		query  string
+		params map[string]InputStruct
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantUnused  []string
+		wantMissing []string
+	}{
+		{
+			name:       "Should accept a query with all its params",
+			args:       args{query: "MATCH (u:User {id: $id}) WHERE u.age > {age} RETURN u", params: map[string]InputStruct{"id": nil, "age": nil}},
+			wantUnused: []string{},
+		},
+		{
+			name:        "Should detect missing params",
+			args:        args{query: "MATCH (u:User {id: $id}) WHERE u.age > $age RETURN u", params: map[string]InputStruct{"id": nil}},
+			wantMissing: []string{"age"},
+		},
+		{
+			name:       "Should ignore placeholders in strings, comments and escaped names",
+			args:       args{query: "RETURN '$id', `$age` // $name", params: map[string]InputStruct{}},
+			wantUnused: []string{},
+		},
+		{
+			name:       "Should detect unused params",
+			args:       args{query: "MATCH (u:User {id: $id}) RETURN u", params: map[string]InputStruct{"id": nil, "b": nil, "a": nil}},
+			wantUnused: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotUnused, err := validateQueryParams(tokenizeCypher(tt.args.query), tt.args.params)
+			if tt.wantMissing != nil {
+				paramErr, isParamErr := err.(*internalErr.ParameterError)
+				if !isParamErr || !reflect.DeepEqual(paramErr.Missing, tt.wantMissing) {
+					t.Errorf("validateQueryParams() error = %v, want missing %v", err, tt.wantMissing)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateQueryParams() error = %v", err)
+			}
+			if !reflect.DeepEqual(gotUnused, tt.wantUnused) {
+				t.Errorf("validateQueryParams() = %v, want %v", gotUnused, tt.wantUnused)
+			}
+		})
+	}
+}
+
+func Test_manager_runWithContext(t *testing.T) {
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	expiringCtx, cancelExpiring := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelExpiring()
+
+	type args struct {
+		ctx  context.Context
+		work func() Neo4GoError
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantErr       bool
+		wantCtxErr    bool
+		wantCause     error
+		wantAbandoned bool
+	}{
+		{
+			name: "Should return the work result when the context is not done",
+			args: args{
+				ctx:  context.Background(),
+				work: func() Neo4GoError { return nil },
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should return the work error when the context is not done",
+			args: args{
+				ctx:  context.Background(),
+				work: func() Neo4GoError { return &internalErr.QueryError{Err: "A typical error"} },
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should not run the work when the context is already done",
+			args: args{
+				ctx: cancelledCtx,
+				work: func() Neo4GoError {
+					t.Error("manager.runWithContext() ran the work with a done context")
+					return nil
+				},
+			},
+			wantErr:       true,
+			wantCtxErr:    true,
+			wantCause:     context.Canceled,
+			wantAbandoned: true,
+		},
+		{
+			name: "Should abandon the work when the context expires before its end",
+			args: args{
+				ctx: expiringCtx,
+				work: func() Neo4GoError {
+					time.Sleep(50 * time.Millisecond)
+					return nil
+				},
+			},
+			wantErr:       true,
+			wantCtxErr:    true,
+			wantCause:     context.DeadlineExceeded,
+			wantAbandoned: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newFakeManager()
+
+			abandoned := make(chan struct{})
+			got := m.runWithContext(tt.args.ctx, tt.args.work, func() { close(abandoned) })
+			if (got != nil) != tt.wantErr {
+				t.Errorf("manager.runWithContext() = %v, wantErr %v", got, tt.wantErr)
+			}
+			if IsContextError(got) != tt.wantCtxErr {
+				t.Errorf("manager.runWithContext() = %v, wantCtxErr %v", got, tt.wantCtxErr)
+			}
+			if tt.wantCause != nil && !errors.Is(got, tt.wantCause) {
+				t.Errorf("manager.runWithContext() = %v, want an error wrapping %v", got, tt.wantCause)
+			}
+			if tt.wantAbandoned {
+				select {
+				case <-abandoned:
+				case <-time.After(time.Second):
+					t.Errorf("manager.runWithContext() did not release the abandoned work")
+				}
+			}
+
+			// The abandoned work is not counted as running anymore once it is released
+			deadline := time.Now().Add(time.Second)
+			for m.runningOperations() != 0 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			if running := m.runningOperations(); running != 0 {
+				t.Errorf("manager.runWithContext() left %d running operations", running)
+			}
+		})
+	}
+}
+
+func Test_withContextTimeout(t *testing.T) {
+	type args struct {
+		timeout     time.Duration
+		configurers []func(*neo4j.TransactionConfig)
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantAtMost time.Duration
+	}{
+		{
+			name: "Should use the context deadline as timeout",
+			args: args{
+				timeout: time.Minute,
+			},
+			wantAtMost: time.Minute,
+		},
+		{
+			name: "Should shorten a longer configured timeout",
+			args: args{
+				timeout:     time.Minute,
+				configurers: []func(*neo4j.TransactionConfig){neo4j.WithTxTimeout(time.Hour)},
+			},
+			wantAtMost: time.Minute,
+		},
+		{
+			name: "Should keep a shorter configured timeout",
+			args: args{
+				timeout:     time.Minute,
+				configurers: []func(*neo4j.TransactionConfig){neo4j.WithTxTimeout(time.Second)},
+			},
+			wantAtMost: time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.args.timeout)
+			defer cancel()
+
+			config := neo4j.TransactionConfig{}
+			for _, configurer := range withContextTimeout(ctx, tt.args.configurers) {
+				configurer(&config)
+			}
+
+			if config.Timeout <= 0 || config.Timeout > tt.wantAtMost {
+				t.Errorf("withContextTimeout() timeout = %v, want at most %v", config.Timeout, tt.wantAtMost)
+			}
+		})
+	}
+}
+
+func Test_validateTransactionConfig(t *testing.T) {
+	appName := "app"
+	type args struct {
+		timeout  time.Duration
+		metadata map[string]InputStruct
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Should allow a timeout and metadata",
+			args: args{
+				timeout:  time.Second,
+				metadata: map[string]InputStruct{"app": NewInputString(&appName)},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Should allow no timeout",
+			args:    args{},
+			wantErr: false,
+		},
+		{
+			name:    "Should not allow a negative timeout",
+			args:    args{timeout: -time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "Should not allow a timeout shorter than a millisecond",
+			args:    args{timeout: time.Microsecond},
+			wantErr: true,
+		},
+		{
+			name:    "Should not allow an empty metadata key",
+			args:    args{metadata: map[string]InputStruct{"": NewInputString(&appName)}},
+			wantErr: true,
+		},
+		{
+			name:    "Should not allow a nil metadata value",
+			args:    args{metadata: map[string]InputStruct{"app": nil}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateTransactionConfig(tt.args.timeout, tt.args.metadata); (err != nil) != tt.wantErr {
+				t.Errorf("validateTransactionConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_manager_transactionConfigurers(t *testing.T) {
+	appName, defaultUser, user := "app", "default", "alice"
+	type args struct {
+		timeout     time.Duration
+		metadata    map[string]InputStruct
+		configurers []func(*neo4j.TransactionConfig)
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantTimeout  time.Duration
+		wantMetadata map[string]string
+	}{
+		{
+			name:         "Should use the defaults of the manager",
+			args:         args{},
+			wantTimeout:  time.Minute,
+			wantMetadata: map[string]string{"app": "app", "user": "default"},
+		},
+		{
+			name: "Should override the defaults of the manager",
+			args: args{
+				timeout:  time.Second,
+				metadata: map[string]InputStruct{"user": NewInputString(&user)},
+			},
+			wantTimeout:  time.Second,
+			wantMetadata: map[string]string{"app": "app", "user": "alice"},
+		},
+		{
+			name: "Should let the raw configurers override the typed config",
+			args: args{
+				timeout:     time.Second,
+				configurers: []func(*neo4j.TransactionConfig){neo4j.WithTxTimeout(time.Hour)},
+			},
+			wantTimeout:  time.Hour,
+			wantMetadata: map[string]string{"app": "app", "user": "default"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newFakeManager()
+			m.options.DefaultTimeout = time.Minute
+			m.options.DefaultMetadata = map[string]InputStruct{
+				"app":  NewInputString(&appName),
+				"user": NewInputString(&defaultUser),
+			}
+
+			config := neo4j.TransactionConfig{}
+			for _, configurer := range m.transactionConfigurers(tt.args.timeout, tt.args.metadata, tt.args.configurers) {
+				configurer(&config)
+			}
+
+			if config.Timeout != tt.wantTimeout {
+				t.Errorf("manager.transactionConfigurers() timeout = %v, want %v", config.Timeout, tt.wantTimeout)
+			}
+			gotMetadata := make(map[string]string)
+			for key, value := range config.Metadata {
+				if stringValue, isString := value.(*string); isString {
+					gotMetadata[key] = *stringValue
+				}
+			}
+			if !reflect.DeepEqual(gotMetadata, tt.wantMetadata) {
+				t.Errorf("manager.transactionConfigurers() metadata = %v, want %v", gotMetadata, tt.wantMetadata)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package neo4go
 
 import (
+	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
 	"github.com/neo4j/neo4j-go-driver/neo4j"
@@ -22,28 +25,130 @@ func (b queryOutputFlag) ToggleQueryOuputFlag(flag queryOutputFlag) queryOutputF
 func (b queryOutputFlag) HasBaseQueryOuputFlag(flag queryOutputFlag) bool           { return (b & flag) != 0 }
 func (b queryOutputFlag) IsZeroQueryOuputFlag() bool                                { return b == 0 }
 
-// Manager is a wrapper around the neo4j-go-driver that simplifies its usage and adds type checking
-type Manager interface {
-	// IsConnected tells if the driver could effectively connect to the database
-	IsConnected() bool
+// ResultMode represents the way the records of a query are fetched
+type ResultMode uint
+
+const (
+	// BUFFERED_RESULT fetches all the records before the query returns, so that the session can be released right away
+	BUFFERED_RESULT ResultMode = iota
+
+	// STREAMED_RESULT fetches the records lazily while they are read, for auto-commit queries only.
+	// The session of an auto-commit query is released when the result is closed or fully read.
+	// The queries of a transaction are always buffered, so that the next query or the end of the transaction cannot interrupt the reading of the result
+	STREAMED_RESULT
+)
+
+// AccessMode represents the kind of server on which an auto-commit query runs
+type AccessMode uint
+
+const (
+	// AUTO_ACCESS_MODE detects the access mode from the query : writing queries and procedure calls run on a writer, the others on any server
+	AUTO_ACCESS_MODE AccessMode = iota
+
+	// READ_ACCESS_MODE runs the query on any server, including the read replicas of a cluster
+	READ_ACCESS_MODE
 
+	// WRITE_ACCESS_MODE runs the query on a writer of the cluster
+	WRITE_ACCESS_MODE
+)
+
+// Querier runs queries. It is implemented by both the manager and the transaction handles,
+// so that the same code can run its queries inside or outside a transaction
+type Querier interface {
 	// Query allows a single query to be made in database, possibly through an existing transaction
 	Query(QueryParams) (QueryResult, Neo4GoError)
 
+	// QueryContext is the same as Query, but aborts the query and rolls back its transaction if the context is done before the end of the query
+	QueryContext(context.Context, QueryParams) (QueryResult, Neo4GoError)
+}
+
+// Transactor begins and ends transactions
+type Transactor interface {
 	// BeginTransaction starts a new transaction and stores it under the returned ID
 	BeginTransaction(TransactionParams) (string, Neo4GoError)
 
+	// BeginTransactionContext is the same as BeginTransaction, but gives up and releases the transaction if the context is done before it could begin.
+	// If the context has a deadline, it is also used as the transaction timeout
+	BeginTransactionContext(context.Context, TransactionParams) (string, Neo4GoError)
+
+	// Begin starts a new transaction and returns a handle on it, which runs queries and ends the transaction without its ID
+	Begin(TransactionParams) (Tx, Neo4GoError)
+
+	// BeginContext is the same as Begin, but gives up and releases the transaction if the context is done before it could begin.
+	// If the context has a deadline, it is also used as the transaction timeout, and the context is applied to every use of the handle
+	BeginContext(context.Context, TransactionParams) (Tx, Neo4GoError)
+
 	// Commit commits the transaction that has the given ID
 	Commit(string) Neo4GoError
 
+	// CommitContext is the same as Commit, but stops waiting for the commit if the context is done before its end.
+	// If the context is already done when called, the transaction is rolled back instead
+	CommitContext(context.Context, string) Neo4GoError
+
 	// Rollback rolls back the transaction that has the given ID
 	Rollback(string) Neo4GoError
 
-	// LastBookmark returns the bookmark obtained by the session that ran the last query
+	// RollbackContext is the same as Rollback, but stops waiting for the rollback if the context is done before its end
+	RollbackContext(context.Context, string) Neo4GoError
+}
+
+// Bookmarker gives the bookmark of the last query run
+type Bookmarker interface {
+	// LastBookmark returns the bookmark obtained by the session that ran the last query.
+	//
+	// Deprecated: the last bookmark is shared by every goroutine using the manager.
+	// Give a BookmarkManager to the queries and transactions to keep a causal chain instead
 	LastBookmark() string
+}
+
+// Manager is a wrapper around the neo4j-go-driver that simplifies its usage and adds type checking
+type Manager interface {
+	Querier
+	Transactor
+	Bookmarker
+
+	// IsConnected tells if the driver could effectively connect to the database. It reaches the database and updates the State of the manager
+	IsConnected() bool
+
+	// State returns the connection state of the manager, as seen by its last connectivity check. It does not reach the database
+	State() ConnectionState
+
+	// OnConnectionStateChange registers a function called after each change of the connection state of the manager
+	OnConnectionStateChange(ConnectionStateCallback)
+
+	// QueryBatch runs every query in a single new transaction, and returns one buffered result per query.
+	// If any query fails, the whole batch is rolled back and a Batch error gives the index of the failing query.
+	// The Transaction, CommitOnSuccess and ResultMode params of the queries are ignored
+	QueryBatch([]QueryParams, TransactionParams) ([]QueryResult, Neo4GoError)
+
+	// QueryBatchContext is the same as QueryBatch, but aborts the batch and rolls back its transaction if the context is done before its end
+	QueryBatchContext(context.Context, []QueryParams, TransactionParams) ([]QueryResult, Neo4GoError)
+
+	// ReadTransaction runs the work inside a new read transaction, then commits it if the work succeeds or rolls it back otherwise.
+	// The work is retried on temporary failures, depending on the retry options of the manager
+	ReadTransaction(TransactionParams, TransactionWork) Neo4GoError
+
+	// ReadTransactionContext is the same as ReadTransaction, but stops retrying and rolls back the transaction if the context is done
+	ReadTransactionContext(context.Context, TransactionParams, TransactionWork) Neo4GoError
+
+	// WriteTransaction runs the work inside a new write transaction, then commits it if the work succeeds or rolls it back otherwise.
+	// The work is retried on temporary failures, depending on the retry options of the manager
+	WriteTransaction(TransactionParams, TransactionWork) Neo4GoError
+
+	// WriteTransactionContext is the same as WriteTransaction, but stops retrying and rolls back the transaction if the context is done
+	WriteTransactionContext(context.Context, TransactionParams, TransactionWork) Neo4GoError
 
-	// Close closes the driver
+	// Transactions returns the information of all the transactions currently open in the manager, from the oldest to the newest
+	Transactions() []TransactionInfo
+
+	// Close stops accepting new queries and transactions, waits for the running ones during the ShutdownTimeout of the options,
+	// then rolls back every open transaction and closes every session and the driver. The returned Shutdown error lists every resource that could not be released
 	Close() Neo4GoError
+
+	// Shutdown stops accepting new queries and transactions, and waits for the running ones until the context is done.
+	// Then, it releases everything like Close does. The queries and commits of the open transactions are still accepted while waiting,
+	// and the transactions still in use once the context is done are released in background
+	Shutdown(context.Context) Neo4GoError
 }
 
 // ManagerOptions represents the configuration applied to a manager
@@ -51,6 +156,18 @@ type ManagerOptions struct {
 	// The full URI to access the database (of the form protocol://domain:port)
 	URI string
 
+	// The URIs of the standby databases, tried in order after URI when it cannot be reached.
+	// URI can be left empty when they are given
+	URIs []string
+
+	// The interval at which the connectivity of the manager is checked in the background, to keep its State up to date.
+	// Zero means no background check, so that the state is only updated by IsConnected
+	HealthCheckInterval time.Duration
+
+	// The function called after the manager switched to another URI because the current one could not be reached anymore.
+	// By default, the switch is logged
+	OnFailover func(previousURI string, newURI string)
+
 	// The name of the database to use
 	DatabaseName string
 
@@ -71,6 +188,35 @@ type ManagerOptions struct {
 
 	// The default formatting to apply to every query of this manager
 	DefaultOutputConfig queryOutputFlag
+
+	// The retry configuration applied to managed transactions (ReadTransaction and WriteTransaction)
+	Retry RetryOptions
+
+	// The duration after which a transaction that did not run any query is automatically rolled back. Zero means no limit
+	TransactionIdleTimeout time.Duration
+
+	// The duration after which a transaction is automatically rolled back, even if it is still used. Zero means no limit.
+	// A transaction stuck in a query is removed from the manager at once, and rolled back as soon as the query returns
+	TransactionMaxAge time.Duration
+
+	// The function called after a transaction was automatically rolled back. By default, the rollback is logged
+	OnTransactionReaped func(TransactionInfo)
+
+	// The maximum duration for which Close waits for the running queries and transactions. Zero means the default (30s), and a negative value does not wait
+	ShutdownTimeout time.Duration
+
+	// Disables the check that every $param placeholder of a query is given in its params, done before running the query
+	SkipParamsValidation bool
+
+	// The function called when a query is given params that it does not use. By default, unused params are ignored
+	OnUnusedParams func(query string, unusedParams []string)
+
+	// The timeout applied to every transaction and auto-commit query that does not set its own. Zero means the timeout of the database
+	DefaultTimeout time.Duration
+
+	// The metadata added to every transaction and auto-commit query, for example to identify the calling service in SHOW TRANSACTIONS.
+	// The metadata of a transaction or query overrides the default values of the same keys
+	DefaultMetadata map[string]InputStruct
 }
 
 // QueryParams represents all the configuration of a single query transaction
@@ -81,12 +227,28 @@ type QueryParams struct {
 	// The parameters to apply to this query
 	Params map[string]InputStruct
 
-	// The configurers to apply to the query
+	// The configurers to apply to the query. They are applied after the timeout and the metadata, so they can override them
 	Configurers []func(*neo4j.TransactionConfig)
 
+	// The timeout of the query, after which the database stops it. Zero means the default timeout of the manager.
+	// It is ignored when the query runs in a transaction, as the timeout of the transaction is used
+	Timeout time.Duration
+
+	// The metadata attached to the query, visible in SHOW TRANSACTIONS.
+	// It is ignored when the query runs in a transaction, as the metadata of the transaction is used
+	Metadata map[string]InputStruct
+
 	// The bookmarks of previous sessions to apply to the query
 	Bookmarks []string
 
+	// The database in which the query runs, instead of the database of the manager.
+	// It cannot differ from the database of the transaction when the query runs in one
+	DatabaseName string
+
+	// The causal chain of the query. The query waits for its bookmarks, and then adds its own bookmark to it.
+	// It is ignored when the query runs in a transaction, as the bookmark manager of the transaction is used
+	BookmarkManager BookmarkManager
+
 	// The transaction ID to use for the query
 	Transaction string
 
@@ -95,6 +257,14 @@ type QueryParams struct {
 
 	// The different configurations to apply to the output of the query
 	OutputConfig queryOutputFlag
+
+	// Tells if the records are all fetched before the query returns (the default), or streamed until the result is closed.
+	// The queries of a transaction are always buffered
+	ResultMode ResultMode
+
+	// The kind of server on which the query runs, which takes precedence over the detection from the query.
+	// It is ignored when the query runs in a transaction, as the access mode of the transaction is used
+	AccessMode AccessMode
 }
 
 // TransactionParams represents all the configuration of a single transaction at its creation
@@ -105,17 +275,82 @@ type TransactionParams struct {
 	// The bookmarks of previous sessions to apply to the query
 	Bookmarks []string
 
-	// The configurers to apply to the transaction
+	// The database in which the transaction runs, instead of the database of the manager
+	DatabaseName string
+
+	// The causal chain of the transaction. The transaction waits for its bookmarks, and then adds its own bookmark to it on commit
+	BookmarkManager BookmarkManager
+
+	// The configurers to apply to the transaction. They are applied after the timeout and the metadata, so they can override them
 	Configurers []func(*neo4j.TransactionConfig)
+
+	// The timeout of the transaction, after which the database rolls it back. Zero means the default timeout of the manager
+	Timeout time.Duration
+
+	// The metadata attached to the transaction, visible in SHOW TRANSACTIONS
+	Metadata map[string]InputStruct
 }
 
 // transactionSession represents a session and a transaction that are stored until the transaction is ended
 type transactionSession struct {
 	// The transaction that has been started
-	transaction neo4j.Transaction
+	transaction Transaction
 
 	// The session that started the transaction
-	session neo4j.Session
+	session Session
+
+	// The driver that opened the session
+	driver Driver
+
+	// The access mode of the session
+	accessMode neo4j.AccessMode
+
+	// The database of the session
+	databaseName string
+
+	// The time at which the transaction began
+	createdAt time.Time
+
+	// The time at which the last query of the transaction started or ended
+	lastUsedAt time.Time
+
+	// The number of queries run in the transaction
+	queryCount int
+
+	// The number of queries of the transaction that are currently running
+	runningQueries int
+
+	// Tells if the transaction is being committed or rolled back, so that it cannot be used nor ended by another goroutine
+	ending bool
+
+	// The bookmarks that the transaction waited for
+	bookmarks []string
+
+	// The causal chain to which the bookmark of the transaction is added on commit
+	bookmarkManager BookmarkManager
+
+	// The mutex used to prevent concurrent use of the transaction, which is not safe for concurrent use in the driver
+	mutex sync.Mutex
+
+	// Ensures that the transaction is only released once
+	releaseOnce sync.Once
+
+	// The error returned when closing the session of the transaction
+	releaseErr error
+}
+
+// release rolls back the transaction if it was not ended yet, then closes its session.
+// Only the first call has an effect, the next ones return the same error
+func (txSession *transactionSession) release() error {
+	txSession.releaseOnce.Do(func() {
+		txSession.mutex.Lock()
+		defer txSession.mutex.Unlock()
+
+		_ = txSession.transaction.Close()
+		txSession.releaseErr = txSession.session.Close()
+	})
+
+	return txSession.releaseErr
 }
 
 // manager is the default implementation of the Manager interface
@@ -123,31 +358,125 @@ type manager struct {
 	// The configuration of this manager
 	options *ManagerOptions
 
-	// The neo4j-go-driver wrapped in this manager
-	driver *neo4j.Driver
+	// The driver wrapped in this manager, which is the neo4j-go-driver unless another one is given
+	driver Driver
+
+	// Tells if the driver was closed, so that no other driver can replace it anymore
+	driverReleased bool
+
+	// The mutex used to prevent concurrent access to the driver
+	driverMutex sync.RWMutex
+
+	// The function creating a driver for a URI, used to switch to another URI. Nil if the driver was given to the manager
+	driverFactory driverFactory
+
+	// The URIs that the manager can use, in order
+	endpoints []string
+
+	// The index of the URI used by the current driver
+	endpointIndex int
+
+	// The mutex used to prevent concurrent switches to another URI
+	failoverMutex sync.Mutex
+
+	// The connection state of the manager, as seen by its last connectivity check
+	state ConnectionState
+
+	// The functions called after each change of the connection state
+	stateCallbacks []ConnectionStateCallback
+
+	// The mutex used to prevent concurrent access to the connection state and its callbacks
+	stateMutex sync.RWMutex
+
+	// The mutex used to serialize the connectivity checks
+	checkMutex sync.Mutex
+
+	// The changes of the connection state whose callbacks were not called yet, in the order they happened
+	pendingStateChanges []stateChange
+
+	// Tells if a goroutine is currently calling the callbacks of the pending changes
+	dispatchingStateChanges bool
+
+	// The mutex used to prevent concurrent access to the pending changes, separate from the check mutex so that callbacks can check the connectivity
+	dispatchMutex sync.Mutex
+
+	// The channel closed to stop the routine that checks the connectivity in the background.
+	// It is protected by the transaction/session mutex
+	stopHealthChecker chan struct{}
 
 	// The bookmark obtained by the session that ran the last query
 	lastBookmark string
 
+	// The mutex used to prevent concurrent access to the last bookmark
+	lastBookmarkMutex sync.RWMutex
+
 	// The map of transactions currently running, with their IDs as keys
-	transactionSessions map[string]transactionSession
+	transactionSessions map[string]*transactionSession
 
-	// The mutex used to prevent concurent writes to the transaction/session map
+	// The mutex used to prevent concurent writes to the transaction/session map and to the stored transactions
 	transactionSessionsMutex sync.RWMutex
+
+	// Tells if the transactions of the manager were released, so that no transaction can be stored anymore.
+	// It is protected by the transaction/session mutex
+	transactionsReleased bool
+
+	// The channel closed to stop the routine that rolls back expired transactions.
+	// It is protected by the transaction/session mutex
+	stopReaper chan struct{}
+
+	// Tells if the manager is shutting down, so that it does not accept new queries nor transactions anymore
+	shuttingDown bool
+
+	// The number of queries, transactions, commits and rollbacks currently running
+	operations int
+
+	// The channel closed once the last running operation ends, created by Shutdown when it must wait for them
+	operationsDone chan struct{}
+
+	// The sessions of the auto-commit queries that are still open, which are closed when shutting down
+	openSessions map[Session]struct{}
+
+	// The mutex used to prevent concurrent access to the shutdown state, the running operations and the open sessions
+	operationsMutex sync.Mutex
+
+	// Ensures that the resources of the manager are only released once
+	releaseOnce sync.Once
+
+	// The error returned when releasing the resources of the manager
+	releaseErr Neo4GoError
 }
 
 // NewManager creates a new instance of Manager, with a given config.
+// Its URIs are tried in order until one of them can be reached
 func NewManager(options ManagerOptions) (Manager, Neo4GoError) {
+	// Check if the config was correctly filled
+	optErr := validateManagerOptions(options)
+	if optErr != nil {
+		return nil, optErr
+	}
+
+	// Create the neo4j-go-driver drivers wrapped by the manager
+	return newManagerWithDriverFactory(options, func(uri string) (Driver, Neo4GoError) {
+		uriOptions := options
+		uriOptions.URI = uri
+		return newNeo4jDriver(uriOptions)
+	})
+}
+
+// NewManagerWithDriver creates a new instance of Manager, with a given config and running on the given driver.
+// The URI, auth and driver configurers of the config are not used, as the driver is already created
+func NewManagerWithDriver(options ManagerOptions, driver Driver) (Manager, Neo4GoError) {
 	newManager := manager{}
 
-	// Init the driver of the manager
-	err := newManager.init(options)
+	// Init the manager with its driver
+	err := newManager.init(options, driver)
 	if err != nil {
 		return nil, err
 	}
 
 	// Test the connection of the driver
 	if !newManager.IsConnected() {
+		_ = newManager.Close()
 		return nil, &internalErr.InitError{
 			Err:    "Could not connect to database",
 			URI:    options.URI,
@@ -158,156 +487,256 @@ func NewManager(options ManagerOptions) (Manager, Neo4GoError) {
 	return &newManager, nil
 }
 
-// init creates a new driver in the manager from the given config.
-func (m *manager) init(options ManagerOptions) Neo4GoError {
+// init sets the driver of the manager and applies the given config.
+func (m *manager) init(options ManagerOptions, driver Driver) Neo4GoError {
 	// Check if the config was correctly filled
 	optErr := validateManagerOptions(options)
 	if optErr != nil {
 		return optErr
 	}
 
-	// Select the right auth protocol
-	usedAuth := neo4j.NoAuth()
-	if options.CustomAuth != nil {
-		usedAuth = *options.CustomAuth
-	} else if options.Username != "" && options.Password != "" {
-		usedAuth = neo4j.BasicAuth(options.Username, options.Password, options.Realm)
-	}
-
-	// Create the driver
-	newDriver, err := neo4j.NewDriver(
-		options.URI,
-		usedAuth,
-		options.Configurers...,
-	)
-
-	if err != nil {
-		return toDriverError(err)
+	if driver == nil {
+		return &internalErr.InitError{
+			Err:    "Driver given to the manager is nil",
+			URI:    options.URI,
+			DBName: options.DatabaseName,
+		}
 	}
 
 	m.options = &options
-	m.driver = &newDriver
+	m.driver = driver
 
-	m.transactionSessions = make(map[string]transactionSession)
+	m.transactionSessions = make(map[string]*transactionSession)
 	m.transactionSessionsMutex = sync.RWMutex{}
+	m.openSessions = make(map[Session]struct{})
+
+	// Start rolling back the expired transactions if a limit was configured
+	if options.TransactionIdleTimeout > 0 || options.TransactionMaxAge > 0 {
+		m.stopReaper = make(chan struct{})
+		go m.runReaper(m.stopReaper)
+	}
+
+	// Start checking the connectivity in the background if an interval was configured
+	if options.HealthCheckInterval > 0 {
+		m.stopHealthChecker = make(chan struct{})
+		go m.runHealthChecker(options.HealthCheckInterval, m.stopHealthChecker)
+	}
 
 	return nil
 }
 
-// IsConnected tells if the driver could effectively connect to the database
+// IsConnected tells if the driver could effectively connect to the database. It reaches the database and updates the State of the manager
 func (m *manager) IsConnected() bool {
-	if m.driver == nil {
-		return false
-	}
-
-	err := (*m.driver).VerifyConnectivity()
+	return m.checkConnection() == CONNECTED_STATE
+}
 
-	return err == nil
+// Query allows a single query to be made in database, possibly through an existing transaction
+func (m *manager) Query(queryParams QueryParams) (QueryResult, Neo4GoError) {
+	return m.QueryContext(context.Background(), queryParams)
 }
 
-// Close closes the driver
-func (m *manager) Close() Neo4GoError {
-	for _, val := range m.transactionSessions {
-		err := val.transaction.Close()
-		if err != nil {
-			return toDriverError(err)
+// QueryContext is the same as Query, but aborts the query and rolls back its transaction if the context is done before the end of the query
+func (m *manager) QueryContext(ctx context.Context, queryParams QueryParams) (QueryResult, Neo4GoError) {
+	if err := validateTransactionConfig(queryParams.Timeout, queryParams.Metadata); err != nil {
+		return nil, &internalErr.QueryError{
+			Err: err.Error(),
 		}
+	}
 
-		err = val.session.Close()
+	// Check the params against the placeholders of the query, to fail before any round trip to the database
+	tokens := tokenizeCypher(queryParams.Query)
+	if !m.options.SkipParamsValidation {
+		unusedParams, err := validateQueryParams(tokens, queryParams.Params)
 		if err != nil {
-			return toDriverError(err)
+			return nil, err
+		}
+		if len(unusedParams) > 0 && m.options.OnUnusedParams != nil {
+			m.options.OnUnusedParams(queryParams.Query, unusedParams)
 		}
 	}
 
-	err := (*m.driver).Close()
-	if err != nil {
-		return toDriverError(err)
-	}
-
-	return nil
-}
-
-// Query allows a single query to be made in database, possibly through an existing transaction
-func (m *manager) Query(queryParams QueryParams) (QueryResult, Neo4GoError) {
-	// First, we convert all the input objects as interface maps
+	// Then, we convert all the input objects as interface maps
 	paramsMap := make(map[string]interface{})
 	for key, value := range queryParams.Params {
 		paramsMap[key] = convertInputObject(value)
 	}
 
-	// Search an existing transaction with the given ID
-	m.transactionSessionsMutex.RLock()
-	txSession, useTransaction := m.transactionSessions[queryParams.Transaction]
-	m.transactionSessionsMutex.RUnlock()
-	if !useTransaction && queryParams.Transaction != "" {
-		return nil, &internalErr.TransactionError{
-			Err: "Trying to query with a non existing transaction",
+	// Search an existing transaction with the given ID, and mark its query as running so that it is not reaped meanwhile
+	var txSession *transactionSession
+	useTransaction := queryParams.Transaction != ""
+
+	// A shutting down manager only accepts the queries of the transactions that are already open
+	if err := m.startOperation(!useTransaction); err != nil {
+		return nil, err
+	}
+	defer m.endOperation()
+
+	if useTransaction {
+		var txErr Neo4GoError
+		txSession, txErr = m.acquireTransaction(queryParams.Transaction)
+		if txErr != nil {
+			return nil, txErr
+		}
+
+		// A transaction cannot switch database, so a query asking for another one must not run in it
+		if queryParams.DatabaseName != "" && queryParams.DatabaseName != txSession.databaseName {
+			m.releaseTransaction(txSession)
+			return nil, &internalErr.QueryError{
+				Err: fmt.Sprintf("The query asks for the database %s, but its transaction runs in the database %s", queryParams.DatabaseName, txSession.databaseName),
+			}
 		}
 	}
 
+	// Chose an output config, with priority on the current query config
+	usedOutConfig := m.options.DefaultOutputConfig
+	if !queryParams.OutputConfig.IsZeroQueryOuputFlag() {
+		usedOutConfig = queryParams.OutputConfig
+	}
+
+	// The records of a transaction are fetched while the transaction is held, as the driver cannot run its next query or end it during the reading
+	isStreamed := queryParams.ResultMode == STREAMED_RESULT && !useTransaction
+
 	var rawResult neo4j.Result
-	var err error
-	var usedSession neo4j.Session
 	if useTransaction {
 		// If the transaction exists, then just run the query with it
-		usedSession = txSession.session
-
-		rawResult, err = txSession.transaction.Run(queryParams.Query, paramsMap)
+		err := m.runWithContext(
+			ctx,
+			func() Neo4GoError {
+				txSession.mutex.Lock()
+				defer txSession.mutex.Unlock()
+
+				var runErr error
+				rawResult, runErr = txSession.transaction.Run(queryParams.Query, paramsMap)
+				if runErr != nil {
+					return toDriverError(runErr)
+				}
+
+				rawResult, runErr = newBufferedResult(rawResult)
+				if runErr != nil {
+					return toDriverError(runErr)
+				}
+
+				return nil
+			},
+			func() { _ = txSession.release() },
+		)
+		m.releaseTransaction(txSession)
 		if err != nil {
-			return nil, toDriverError(err)
+			// The transaction cannot be used anymore once one of its queries was aborted
+			if IsContextError(err) {
+				m.forgetTransaction(queryParams.Transaction)
+			}
+			m.failoverOnError(err, txSession.driver)
+			return nil, err
 		}
 
 		if queryParams.CommitOnSuccess {
-			err := m.Commit(queryParams.Transaction)
+			err := m.CommitContext(ctx, queryParams.Transaction)
 			if err != nil {
 				return nil, err
 			}
 		}
-	} else {
-		// If the transaction does not exist, run the query as auto-commit transaction from a new session
 
-		// Determine if the query is read or write and set the access mode depending on it
-		isWrite := IsWriteQuery(queryParams.Query)
+		// The session of the transaction is released when the transaction ends, not when the result is closed
+		return newQueryResult(rawResult, usedOutConfig, nil), nil
+	}
 
-		usedSessionMode := neo4j.AccessModeRead
-		if isWrite {
-			usedSessionMode = neo4j.AccessModeWrite
-		}
+	// If the transaction does not exist, run the query as auto-commit transaction from a new session
 
-		// Create the new session from configuration
-		usedSession, err = (*m.driver).NewSession(neo4j.SessionConfig{
-			AccessMode:   usedSessionMode,
-			DatabaseName: m.options.DatabaseName,
-			Bookmarks:    queryParams.Bookmarks,
-		})
-		if err != nil {
-			return nil, toDriverError(err)
-		}
-		defer usedSession.Close()
+	// Use the access mode asked for the query, or detect it from the query
+	usedSessionMode := sessionAccessMode(queryParams.AccessMode, tokens)
 
-		// Run the query with the new session and the query config
-		rawResult, err = usedSession.Run(queryParams.Query, paramsMap, queryParams.Configurers...)
-		if err != nil {
-			return nil, toDriverError(err)
-		}
-	}
+	usedBookmarks := sessionBookmarks(queryParams.Bookmarks, queryParams.BookmarkManager)
 
-	// Chose an output config, with priority on the current query config
-	usedOutConfig := m.options.DefaultOutputConfig
-	if !queryParams.OutputConfig.IsZeroQueryOuputFlag() {
-		usedOutConfig = queryParams.OutputConfig
-	}
+	usedDriver := m.currentDriver()
 
-	// Convert the raw results as a collection of typed results
-	convertedResult := newQueryResult(rawResult, usedOutConfig)
+	var usedSession Session
+	err := m.runWithContext(
+		ctx,
+		func() Neo4GoError {
+			// Create the new session from configuration
+			var runErr error
+			usedSession, runErr = usedDriver.NewSession(neo4j.SessionConfig{
+				AccessMode:   usedSessionMode,
+				DatabaseName: m.databaseName(queryParams.DatabaseName),
+				Bookmarks:    usedBookmarks,
+			})
+			if runErr != nil {
+				return toDriverError(runErr)
+			}
+			if trackErr := m.trackSession(usedSession); trackErr != nil {
+				_ = usedSession.Close()
+				return trackErr
+			}
 
-	m.lastBookmark = usedSession.LastBookmark()
+			// Run the query with the new session and the query config
+			rawResult, runErr = usedSession.Run(queryParams.Query, paramsMap, withContextTimeout(ctx, m.transactionConfigurers(queryParams.Timeout, queryParams.Metadata, queryParams.Configurers))...)
+			if runErr != nil {
+				_ = m.closeSession(usedSession)
+				return toDriverError(runErr)
+			}
 
-	return convertedResult, nil
+			if isStreamed {
+				return nil
+			}
+
+			// Fetch all the records before releasing the session, so that they stay readable after it is closed
+			rawResult, runErr = newBufferedResult(rawResult)
+			if runErr == nil {
+				m.recordBookmark(queryParams.BookmarkManager, usedBookmarks, usedSession.LastBookmark())
+			}
+			closeErr := m.closeSession(usedSession)
+			if runErr != nil {
+				return toDriverError(runErr)
+			}
+			if closeErr != nil {
+				return toDriverError(closeErr)
+			}
+
+			return nil
+		},
+		func() {
+			// If the query was abandoned, release its session as soon as it is done
+			if usedSession != nil {
+				_ = m.closeSession(usedSession)
+			}
+		},
+	)
+	if err != nil {
+		m.failoverOnError(err, usedDriver)
+		return nil, err
+	}
+
+	if !isStreamed {
+		return newQueryResult(rawResult, usedOutConfig, nil), nil
+	}
+
+	// A streamed result keeps its session open until it is closed or fully read
+	return newQueryResult(rawResult, usedOutConfig, func() error {
+		closeErr := m.closeSession(usedSession)
+		m.recordBookmark(queryParams.BookmarkManager, usedBookmarks, usedSession.LastBookmark())
+		return closeErr
+	}), nil
 }
 
 // BeginTransaction starts a new transaction and stores it under the returned ID
 func (m *manager) BeginTransaction(params TransactionParams) (string, Neo4GoError) {
+	return m.BeginTransactionContext(context.Background(), params)
+}
+
+// BeginTransactionContext is the same as BeginTransaction, but gives up and releases the transaction if the context is done before it could begin
+func (m *manager) BeginTransactionContext(ctx context.Context, params TransactionParams) (string, Neo4GoError) {
+	if err := validateTransactionConfig(params.Timeout, params.Metadata); err != nil {
+		return "", &internalErr.TransactionError{
+			Err: err.Error(),
+		}
+	}
+
+	if err := m.startOperation(true); err != nil {
+		return "", err
+	}
+	defer m.endOperation()
+
 	newTxUUID, err := uuid.NewV4()
 	if err != nil {
 		return "", &internalErr.TransactionError{
@@ -321,99 +750,260 @@ func (m *manager) BeginTransaction(params TransactionParams) (string, Neo4GoErro
 		usedSessionMode = neo4j.AccessModeWrite
 	}
 
-	session, err := (*m.driver).NewSession(neo4j.SessionConfig{
-		AccessMode:   usedSessionMode,
-		DatabaseName: m.options.DatabaseName,
-		Bookmarks:    params.Bookmarks,
-	})
-	if err != nil {
-		return "", toDriverError(err)
-	}
+	usedBookmarks := sessionBookmarks(params.Bookmarks, params.BookmarkManager)
+	usedDatabaseName := m.databaseName(params.DatabaseName)
+
+	usedDriver := m.currentDriver()
+
+	var session Session
+	var tx Transaction
+	beginErr := m.runWithContext(
+		ctx,
+		func() Neo4GoError {
+			var runErr error
+			session, runErr = usedDriver.NewSession(neo4j.SessionConfig{
+				AccessMode:   usedSessionMode,
+				DatabaseName: usedDatabaseName,
+				Bookmarks:    usedBookmarks,
+			})
+			if runErr != nil {
+				return toDriverError(runErr)
+			}
 
-	// Then begin the transaction
-	tx, err := session.BeginTransaction(params.Configurers...)
-	if err != nil {
-		closeErr := session.Close()
-		if closeErr != nil {
-			return "", toDriverError(closeErr)
-		}
-		return "", toDriverError(err)
+			// Then begin the transaction
+			tx, runErr = session.BeginTransaction(withContextTimeout(ctx, m.transactionConfigurers(params.Timeout, params.Metadata, params.Configurers))...)
+			if runErr != nil {
+				closeErr := session.Close()
+				if closeErr != nil {
+					return toDriverError(closeErr)
+				}
+				return toDriverError(runErr)
+			}
+
+			return nil
+		},
+		func() {
+			// If the transaction was abandoned, release it as soon as it has begun
+			if tx != nil {
+				_ = tx.Close()
+			}
+			if session != nil {
+				_ = session.Close()
+			}
+		},
+	)
+	if beginErr != nil {
+		m.failoverOnError(beginErr, usedDriver)
+		return "", beginErr
 	}
 
 	// Finally, store the transaction and its session to the manager's map
 	newTxID := newTxUUID.String()
 
+	now := time.Now()
+
 	m.transactionSessionsMutex.Lock()
-	m.transactionSessions[newTxID] = transactionSession{
-		session:     session,
-		transaction: tx,
+	if m.transactionsReleased {
+		// The manager was closed while the transaction was beginning, so it would never be released
+		m.transactionSessionsMutex.Unlock()
+		_ = tx.Close()
+		_ = session.Close()
+		return "", shutdownError()
+	}
+	m.transactionSessions[newTxID] = &transactionSession{
+		session:         session,
+		driver:          usedDriver,
+		transaction:     tx,
+		accessMode:      usedSessionMode,
+		databaseName:    usedDatabaseName,
+		createdAt:       now,
+		lastUsedAt:      now,
+		bookmarks:       usedBookmarks,
+		bookmarkManager: params.BookmarkManager,
 	}
 	m.transactionSessionsMutex.Unlock()
 
 	return newTxID, nil
 }
 
+// Begin starts a new transaction and returns a handle on it
+func (m *manager) Begin(params TransactionParams) (Tx, Neo4GoError) {
+	return m.BeginContext(context.Background(), params)
+}
+
+// BeginContext is the same as Begin, but gives up and releases the transaction if the context is done before it could begin
+func (m *manager) BeginContext(ctx context.Context, params TransactionParams) (Tx, Neo4GoError) {
+	txID, err := m.BeginTransactionContext(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return newManagerTx(ctx, m, txID), nil
+}
+
 // Commit commits the transaction that has the given ID
 func (m *manager) Commit(txID string) Neo4GoError {
+	return m.CommitContext(context.Background(), txID)
+}
+
+// CommitContext is the same as Commit, but stops waiting for the commit if the context is done before its end.
+// If the context is already done when called, the transaction is rolled back instead
+func (m *manager) CommitContext(ctx context.Context, txID string) Neo4GoError {
+	// Commits and rollbacks are still accepted while shutting down, so that the running transactions can end
+	_ = m.startOperation(false)
+	defer m.endOperation()
+
 	// Get the transaction and its session from ID
-	m.transactionSessionsMutex.RLock()
-	txSession, exists := m.transactionSessions[txID]
-	m.transactionSessionsMutex.RUnlock()
-	if !exists {
-		return &internalErr.TransactionError{
-			Err: "Trying to commit a non existing transaction",
-		}
+	txSession, claimErr := m.claimTransaction(txID, "commit")
+	if claimErr != nil {
+		return claimErr
 	}
 
-	// Commit the transaction and close its session
-	err := txSession.transaction.Commit()
-	if err != nil {
-		return toDriverError(err)
+	// Never commit a transaction whose context is already done
+	if ctx.Err() != nil {
+		m.forgetTransaction(txID)
+		_ = txSession.release()
+		return toContextError(ctx.Err())
 	}
-	err = txSession.session.Close()
-	if err != nil {
-		return toDriverError(err)
+
+	// Commit the transaction and close its session
+	err := m.runWithContext(
+		ctx,
+		func() Neo4GoError {
+			txSession.mutex.Lock()
+			err := txSession.transaction.Commit()
+			txSession.mutex.Unlock()
+			if err != nil {
+				return toDriverError(err)
+			}
+			// The bookmark of the session is only updated by the commit
+			m.recordBookmark(txSession.bookmarkManager, txSession.bookmarks, txSession.session.LastBookmark())
+			err = txSession.release()
+			if err != nil {
+				return toDriverError(err)
+			}
+			return nil
+		},
+		func() { _ = txSession.release() },
+	)
+	if err != nil && !IsContextError(err) {
+		// The transaction can still be rolled back
+		m.unclaimTransaction(txSession)
+		m.failoverOnError(err, txSession.driver)
+		return err
 	}
 
 	// Remove the transaction from the manager store
-	m.transactionSessionsMutex.Lock()
-	delete(m.transactionSessions, txID)
-	m.transactionSessionsMutex.Unlock()
+	m.forgetTransaction(txID)
 
-	return nil
+	return err
 }
 
 // Rollback rolls back the transaction that has the given ID
 func (m *manager) Rollback(txID string) Neo4GoError {
+	return m.RollbackContext(context.Background(), txID)
+}
+
+// RollbackContext is the same as Rollback, but stops waiting for the rollback if the context is done before its end
+func (m *manager) RollbackContext(ctx context.Context, txID string) Neo4GoError {
+	// Commits and rollbacks are still accepted while shutting down, so that the running transactions can end
+	_ = m.startOperation(false)
+	defer m.endOperation()
+
 	// Get the transaction and its session from ID
-	m.transactionSessionsMutex.RLock()
-	txSession, exists := m.transactionSessions[txID]
-	m.transactionSessionsMutex.RUnlock()
-	if !exists {
-		return &internalErr.TransactionError{
-			Err: "Trying to rollback a non existing transaction",
-		}
+	txSession, claimErr := m.claimTransaction(txID, "rollback")
+	if claimErr != nil {
+		return claimErr
 	}
 
-	// Commit the transaction and close its session
-	err := txSession.transaction.Rollback()
-	if err != nil {
-		return toDriverError(err)
+	// If the context is already done, closing the transaction still rolls it back
+	if ctx.Err() != nil {
+		m.forgetTransaction(txID)
+		_ = txSession.release()
+		return toContextError(ctx.Err())
 	}
-	err = txSession.session.Close()
-	if err != nil {
-		return toDriverError(err)
+
+	// Rollback the transaction and close its session
+	err := m.runWithContext(
+		ctx,
+		func() Neo4GoError {
+			txSession.mutex.Lock()
+			err := txSession.transaction.Rollback()
+			txSession.mutex.Unlock()
+			if err != nil {
+				return toDriverError(err)
+			}
+			err = txSession.release()
+			if err != nil {
+				return toDriverError(err)
+			}
+			return nil
+		},
+		func() { _ = txSession.release() },
+	)
+	if err != nil && !IsContextError(err) {
+		m.unclaimTransaction(txSession)
+		return err
 	}
 
 	// Remove the transaction from the manager store
+	m.forgetTransaction(txID)
+
+	return err
+}
+
+// claimTransaction returns the transaction that has the given ID and marks it as ending,
+// so that no other goroutine can use it or end it at the same time. The action is only used in errors
+func (m *manager) claimTransaction(txID string, action string) (*transactionSession, Neo4GoError) {
+	m.transactionSessionsMutex.Lock()
+	defer m.transactionSessionsMutex.Unlock()
+
+	txSession, exists := m.transactionSessions[txID]
+	if !exists {
+		return nil, &internalErr.TransactionError{
+			Err: fmt.Sprintf("Trying to %s a non existing transaction", action),
+		}
+	}
+	if txSession.ending {
+		return nil, &internalErr.TransactionError{
+			Err: fmt.Sprintf("Trying to %s a transaction that is already being committed or rolled back", action),
+		}
+	}
+
+	txSession.ending = true
+	return txSession, nil
+}
+
+// unclaimTransaction marks a claimed transaction as usable again, after it failed to end
+func (m *manager) unclaimTransaction(txSession *transactionSession) {
+	m.transactionSessionsMutex.Lock()
+	txSession.ending = false
+	m.transactionSessionsMutex.Unlock()
+}
+
+// forgetTransaction removes the transaction that has the given ID from the manager store
+func (m *manager) forgetTransaction(txID string) {
 	m.transactionSessionsMutex.Lock()
 	delete(m.transactionSessions, txID)
 	m.transactionSessionsMutex.Unlock()
+}
 
-	return nil
+// recordBookmark stores the bookmark obtained by a query or a transaction as the last bookmark of the manager,
+// and adds it to the causal chain of the query or transaction if there is one
+func (m *manager) recordBookmark(bookmarkManager BookmarkManager, usedBookmarks []string, bookmark string) {
+	m.lastBookmarkMutex.Lock()
+	m.lastBookmark = bookmark
+	m.lastBookmarkMutex.Unlock()
+
+	if bookmarkManager != nil {
+		bookmarkManager.UpdateBookmarks(usedBookmarks, bookmark)
+	}
 }
 
 // LastBookmark returns the bookmark obtained by the session that ran the last query
 func (m *manager) LastBookmark() string {
+	m.lastBookmarkMutex.RLock()
+	defer m.lastBookmarkMutex.RUnlock()
+
 	return m.lastBookmark
 }
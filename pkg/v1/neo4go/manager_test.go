@@ -0,0 +1,675 @@
+package neo4go
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// newFakeManager creates a manager running on a fake driver that returns the given records
+func newFakeManager(records ...neo4j.Record) (*manager, *fakeDriver) {
+	driver := &fakeDriver{records: records}
+
+	m, err := NewManagerWithDriver(ManagerOptions{URI: "bolt://localhost:7687", DatabaseName: "neo4j"}, driver)
+	if err != nil {
+		panic(err)
+	}
+
+	return m.(*manager), driver
+}
+
+// runningOperations returns the number of operations that a shutdown of the manager would wait for
+func (m *manager) runningOperations() int {
+	m.operationsMutex.Lock()
+	defer m.operationsMutex.Unlock()
+
+	return m.operations
+}
+
+func TestNewManagerWithDriver(t *testing.T) {
+	type args struct {
+		options ManagerOptions
+		driver  Driver
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Should create a manager on a connected driver",
+			args: args{
+				options: ManagerOptions{URI: "bolt://localhost:7687", DatabaseName: "neo4j"},
+				driver:  &fakeDriver{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should not create a manager on a disconnected driver",
+			args: args{
+				options: ManagerOptions{URI: "bolt://localhost:7687", DatabaseName: "neo4j"},
+				driver:  &fakeDriver{connectErr: errors.New("connection refused")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should not create a manager without driver",
+			args: args{
+				options: ManagerOptions{URI: "bolt://localhost:7687", DatabaseName: "neo4j"},
+				driver:  nil,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should not create a manager with invalid options",
+			args: args{
+				options: ManagerOptions{URI: "bolt://localhost:7687"},
+				driver:  &fakeDriver{},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewManagerWithDriver(tt.args.options, tt.args.driver)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewManagerWithDriver() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !IsInitError(err) {
+				t.Errorf("NewManagerWithDriver() error = %v, want an Init error", err)
+			}
+		})
+	}
+}
+
+func Test_manager_Query(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   QueryParams
+		runErr   error
+		wantMode neo4j.AccessMode
+		wantErr  bool
+	}{
+		{
+			name:     "Should run a read query on a read session",
+			params:   QueryParams{Query: "MATCH (u:User) RETURN u"},
+			wantMode: neo4j.AccessModeRead,
+		},
+		{
+			name:     "Should run a write query on a write session",
+			params:   QueryParams{Query: "CREATE (u:User) RETURN u"},
+			wantMode: neo4j.AccessModeWrite,
+		},
+		{
+			name:     "Should convert the driver errors",
+			params:   QueryParams{Query: "MATCH (u:User) RETURN u"},
+			runErr:   errors.New("A typical error"),
+			wantMode: neo4j.AccessModeRead,
+			wantErr:  true,
+		},
+		{
+			name:    "Should not run a query on a non existing transaction",
+			params:  QueryParams{Query: "MATCH (u:User) RETURN u", Transaction: "unknown"},
+			wantErr: true,
+		},
+		{
+			name:    "Should not run a query with missing params",
+			params:  QueryParams{Query: "MATCH (u:User {id: $id}) RETURN u"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, driver := newFakeManager()
+			driver.runErr = tt.runErr
+
+			_, err := m.Query(tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("manager.Query() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(driver.sessions) == 0 {
+				return
+			}
+
+			session := driver.sessions[0]
+			if session.config.AccessMode != tt.wantMode || session.config.DatabaseName != "neo4j" {
+				t.Errorf("manager.Query() session config = %+v, want mode %v on database neo4j", session.config, tt.wantMode)
+			}
+			if !session.closed {
+				t.Errorf("manager.Query() did not close its session")
+			}
+		})
+	}
+}
+
+func Test_manager_Transaction(t *testing.T) {
+	tests := []struct {
+		name           string
+		end            func(*manager, string) Neo4GoError
+		wantCommitted  bool
+		wantRolledBack bool
+	}{
+		{
+			name:          "Should commit a transaction and close its session",
+			end:           func(m *manager, txID string) Neo4GoError { return m.Commit(txID) },
+			wantCommitted: true,
+		},
+		{
+			name: "Should commit a transaction after a successful query",
+			end: func(m *manager, txID string) Neo4GoError {
+				_, err := m.Query(QueryParams{Query: "CREATE (u:User)", Transaction: txID, CommitOnSuccess: true})
+				return err
+			},
+			wantCommitted: true,
+		},
+		{
+			name:           "Should roll back a transaction and close its session",
+			end:            func(m *manager, txID string) Neo4GoError { return m.Rollback(txID) },
+			wantRolledBack: true,
+		},
+		{
+			name:           "Should roll back the open transactions when closing",
+			end:            func(m *manager, txID string) Neo4GoError { return m.Close() },
+			wantRolledBack: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, driver := newFakeManager()
+
+			txID, err := m.BeginTransaction(TransactionParams{IsWrite: true})
+			if err != nil {
+				t.Fatalf("manager.BeginTransaction() error = %v", err)
+			}
+			if _, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u", Transaction: txID}); err != nil {
+				t.Fatalf("manager.Query() error = %v", err)
+			}
+
+			if err := tt.end(m, txID); err != nil {
+				t.Fatalf("ending the transaction error = %v", err)
+			}
+
+			session := driver.sessions[0]
+			tx := session.transactions[0]
+			if session.config.AccessMode != neo4j.AccessModeWrite {
+				t.Errorf("manager.BeginTransaction() session mode = %v, want write", session.config.AccessMode)
+			}
+			if len(session.queries) != 0 || len(tx.queries) == 0 {
+				t.Errorf("manager.Query() did not run the query in the transaction")
+			}
+			if tx.committed != tt.wantCommitted || tx.rolledBack != tt.wantRolledBack {
+				t.Errorf("transaction committed = %v / rolled back = %v, want %v / %v", tx.committed, tx.rolledBack, tt.wantCommitted, tt.wantRolledBack)
+			}
+			if !session.closed {
+				t.Errorf("transaction session was not closed")
+			}
+			if len(m.Transactions()) != 0 {
+				t.Errorf("transaction is still stored in the manager")
+			}
+			if err := m.Commit(txID); !IsTransactionError(err) {
+				t.Errorf("manager.Commit() on an ended transaction error = %v, want a Transaction error", err)
+			}
+		})
+	}
+}
+
+func Test_manager_Transaction_DoneContext(t *testing.T) {
+	tests := []struct {
+		name string
+		end  func(*manager, context.Context, string) Neo4GoError
+	}{
+		{
+			name: "Should roll back and close the session of a transaction committed with a done context",
+			end:  func(m *manager, ctx context.Context, txID string) Neo4GoError { return m.CommitContext(ctx, txID) },
+		},
+		{
+			name: "Should roll back and close the session of a transaction rolled back with a done context",
+			end:  func(m *manager, ctx context.Context, txID string) Neo4GoError { return m.RollbackContext(ctx, txID) },
+		},
+		{
+			name: "Should roll back and close the session of a transaction queried with a done context",
+			end: func(m *manager, ctx context.Context, txID string) Neo4GoError {
+				_, err := m.QueryContext(ctx, QueryParams{Query: "MATCH (u:User) RETURN u", Transaction: txID})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, driver := newFakeManager()
+
+			txID, err := m.BeginTransaction(TransactionParams{IsWrite: true})
+			if err != nil {
+				t.Fatalf("manager.BeginTransaction() error = %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if err := tt.end(m, ctx, txID); !IsContextError(err) || !errors.Is(err, context.Canceled) {
+				t.Fatalf("ending the transaction error = %v, want a Context error wrapping %v", err, context.Canceled)
+			}
+
+			session := driver.sessions[0]
+			tx := session.transactions[0]
+			if tx.committed || !tx.rolledBack {
+				t.Errorf("transaction committed = %v / rolled back = %v, want false / true", tx.committed, tx.rolledBack)
+			}
+			if session.closeCount != 1 {
+				t.Errorf("transaction session closed %d times, want 1", session.closeCount)
+			}
+			if len(m.Transactions()) != 0 {
+				t.Errorf("transaction is still stored in the manager")
+			}
+		})
+	}
+}
+
+func Test_manager_Query_AbandonedQuery(t *testing.T) {
+	m, driver := newFakeManager()
+
+	txID, err := m.BeginTransaction(TransactionParams{IsWrite: true})
+	if err != nil {
+		t.Fatalf("manager.BeginTransaction() error = %v", err)
+	}
+	session := driver.sessions[0]
+	fakeTx := session.transactions[0]
+	fakeTx.runBlock = make(chan struct{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := m.QueryContext(ctx, QueryParams{Query: "CREATE (u:User)", Transaction: txID}); !IsContextError(err) {
+		t.Fatalf("manager.QueryContext() error = %v, want a Context error", err)
+	}
+
+	// The query is still running in the driver, so a shutdown must wait for it instead of closing its session
+	shutdownDone := make(chan Neo4GoError)
+	go func() {
+		shutdownDone <- m.Shutdown(context.Background())
+	}()
+	select {
+	case err := <-shutdownDone:
+		t.Fatalf("manager.Shutdown() = %v before the abandoned query returned", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(fakeTx.runBlock)
+	if err := <-shutdownDone; err != nil {
+		t.Errorf("manager.Shutdown() error = %v", err)
+	}
+	if fakeTx.committed || !fakeTx.rolledBack || session.closeCount != 1 {
+		t.Errorf("abandoned transaction committed = %v / rolled back = %v / session closed %d times, want false / true / 1", fakeTx.committed, fakeTx.rolledBack, session.closeCount)
+	}
+}
+
+func Test_manager_Close(t *testing.T) {
+	m, driver := newFakeManager()
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("manager.Close() error = %v", err)
+	}
+	if !driver.closed {
+		t.Errorf("manager.Close() did not close the driver")
+	}
+}
+
+func Test_manager_Query_ResultMode(t *testing.T) {
+	records := []neo4j.Record{
+		&fakeRecord{keys: []string{"name"}, values: []interface{}{"Alice"}},
+		&fakeRecord{keys: []string{"name"}, values: []interface{}{"Bob"}},
+	}
+
+	tests := []struct {
+		name                  string
+		mode                  ResultMode
+		wantOpenAfterQuery    bool
+		wantOpenAfterIterated bool
+	}{
+		{
+			name:                  "Should release the session of a buffered result before returning",
+			mode:                  BUFFERED_RESULT,
+			wantOpenAfterQuery:    false,
+			wantOpenAfterIterated: false,
+		},
+		{
+			name:                  "Should keep the session of a streamed result until it is fully read",
+			mode:                  STREAMED_RESULT,
+			wantOpenAfterQuery:    true,
+			wantOpenAfterIterated: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, driver := newFakeManager(records...)
+
+			result, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u.name AS name", ResultMode: tt.mode})
+			if err != nil {
+				t.Fatalf("manager.Query() error = %v", err)
+			}
+
+			session := driver.sessions[0]
+			if !session.closed != tt.wantOpenAfterQuery {
+				t.Errorf("manager.Query() session open = %v, want %v", !session.closed, tt.wantOpenAfterQuery)
+			}
+
+			collected, err := Collect(result, nil)
+			if err != nil {
+				t.Fatalf("Collect() error = %v", err)
+			}
+			if len(collected) != 2 || collected[0].Strings["name"] != "Alice" || collected[1].Strings["name"] != "Bob" {
+				t.Errorf("Collect() = %+v, want Alice and Bob", collected)
+			}
+
+			if !session.closed != tt.wantOpenAfterIterated {
+				t.Errorf("Collect() session open = %v, want %v", !session.closed, tt.wantOpenAfterIterated)
+			}
+		})
+	}
+}
+
+func Test_manager_Query_StreamedTransaction(t *testing.T) {
+	tests := []struct {
+		name            string
+		commitOnSuccess bool
+	}{
+		{name: "Should buffer the result of a transaction query before the transaction is committed"},
+		{name: "Should buffer the result of a query that commits its transaction", commitOnSuccess: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, driver := newFakeManager(&fakeRecord{keys: []string{"name"}, values: []interface{}{"Alice"}})
+
+			txID, err := m.BeginTransaction(TransactionParams{IsWrite: true})
+			if err != nil {
+				t.Fatalf("manager.BeginTransaction() error = %v", err)
+			}
+			result, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u.name AS name", Transaction: txID, CommitOnSuccess: tt.commitOnSuccess, ResultMode: STREAMED_RESULT})
+			if err != nil {
+				t.Fatalf("manager.Query() error = %v", err)
+			}
+
+			// The query is not running anymore, so the transaction can run another query or end while the result is read
+			if !tt.commitOnSuccess {
+				if infos := m.Transactions(); len(infos) != 1 || infos[0].RunningQueries != 0 {
+					t.Errorf("manager.Transactions() = %+v, want a transaction without running queries", infos)
+				}
+				if err := m.Commit(txID); err != nil {
+					t.Fatalf("manager.Commit() error = %v", err)
+				}
+			}
+			if session := driver.sessions[0]; !session.transactions[0].committed || !session.closed {
+				t.Fatalf("the transaction was not committed and its session closed")
+			}
+
+			// The records were fetched before the commit closed the session
+			collected, err := Collect(result, nil)
+			if err != nil {
+				t.Fatalf("Collect() error = %v", err)
+			}
+			if len(collected) != 1 || collected[0].Strings["name"] != "Alice" {
+				t.Errorf("Collect() = %+v, want Alice", collected)
+			}
+		})
+	}
+}
+
+func Test_manager_Query_StreamedResultClose(t *testing.T) {
+	m, driver := newFakeManager(&fakeRecord{keys: []string{"name"}, values: []interface{}{"Alice"}})
+
+	result, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u.name AS name", ResultMode: STREAMED_RESULT})
+	if err != nil {
+		t.Fatalf("manager.Query() error = %v", err)
+	}
+
+	if err := result.Close(); err != nil {
+		t.Fatalf("QueryResult.Close() error = %v", err)
+	}
+	if !driver.sessions[0].closed {
+		t.Errorf("QueryResult.Close() did not close the session")
+	}
+	if err := result.Close(); err != nil {
+		t.Errorf("QueryResult.Close() called twice error = %v", err)
+	}
+}
+
+func Test_manager_Query_StreamedResultHelpers(t *testing.T) {
+	alice := &fakeRecord{keys: []string{"name"}, values: []interface{}{"Alice"}}
+	bob := &fakeRecord{keys: []string{"name"}, values: []interface{}{"Bob"}}
+
+	tests := []struct {
+		name      string
+		records   []neo4j.Record
+		resultErr error
+		read      func(QueryResult) Neo4GoError
+	}{
+		{
+			name:    "Should close the session when Single finds more than one record",
+			records: []neo4j.Record{alice, bob},
+			read: func(result QueryResult) Neo4GoError {
+				_, err := Single(result, nil)
+				return err
+			},
+		},
+		{
+			name: "Should close the session when Single finds no record",
+			read: func(result QueryResult) Neo4GoError {
+				_, err := Single(result, nil)
+				return err
+			},
+		},
+		{
+			name:      "Should close the session when Single fails in the middle of the stream",
+			records:   []neo4j.Record{alice},
+			resultErr: errors.New("connection lost"),
+			read: func(result QueryResult) Neo4GoError {
+				_, err := Single(result, nil)
+				return err
+			},
+		},
+		{
+			name:      "Should close the session when Collect fails in the middle of the stream",
+			records:   []neo4j.Record{alice, bob},
+			resultErr: errors.New("connection lost"),
+			read: func(result QueryResult) Neo4GoError {
+				_, err := Collect(result, nil)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, driver := newFakeManager(tt.records...)
+			driver.resultErr = tt.resultErr
+
+			result, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u.name AS name", ResultMode: STREAMED_RESULT})
+			if err != nil {
+				t.Fatalf("manager.Query() error = %v", err)
+			}
+
+			if err := tt.read(result); err == nil {
+				t.Errorf("reading the result error = nil, want an error")
+			}
+			if session := driver.sessions[0]; !session.closed || session.closeCount != 1 {
+				t.Errorf("reading the result closed the session %d times, want 1", session.closeCount)
+			}
+		})
+	}
+}
+
+func Test_manager_Query_UnusedParams(t *testing.T) {
+	m, _ := newFakeManager()
+
+	var gotUnused []string
+	m.options.OnUnusedParams = func(query string, unusedParams []string) {
+		gotUnused = unusedParams
+	}
+
+	_, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u", Params: map[string]InputStruct{"id": nil}})
+	if err != nil {
+		t.Fatalf("manager.Query() error = %v", err)
+	}
+	if !reflect.DeepEqual(gotUnused, []string{"id"}) {
+		t.Errorf("manager.Query() unused params = %v, want [id]", gotUnused)
+	}
+
+	m.options.SkipParamsValidation = true
+	gotUnused = nil
+	if _, err := m.Query(QueryParams{Query: "MATCH (u:User {id: $id}) RETURN u"}); err != nil {
+		t.Fatalf("manager.Query() without validation error = %v", err)
+	}
+	if gotUnused != nil {
+		t.Errorf("manager.Query() without validation reported unused params %v", gotUnused)
+	}
+}
+
+func Test_manager_BookmarkManager(t *testing.T) {
+	m, driver := newFakeManager()
+	bm := NewBookmarkManager("start")
+
+	if _, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u", BookmarkManager: bm}); err != nil {
+		t.Fatalf("manager.Query() error = %v", err)
+	}
+	if got := driver.sessions[0].config.Bookmarks; !reflect.DeepEqual(got, []string{"start"}) {
+		t.Errorf("manager.Query() session bookmarks = %v, want [start]", got)
+	}
+	if got := bm.Bookmarks(); !reflect.DeepEqual(got, []string{"bookmark"}) {
+		t.Errorf("BookmarkManager.Bookmarks() after query = %v, want [bookmark]", got)
+	}
+
+	other := NewBookmarkManager("other")
+	txID, err := m.BeginTransaction(TransactionParams{IsWrite: true, BookmarkManager: other})
+	if err != nil {
+		t.Fatalf("manager.BeginTransaction() error = %v", err)
+	}
+	if got := driver.sessions[1].config.Bookmarks; !reflect.DeepEqual(got, []string{"other"}) {
+		t.Errorf("manager.BeginTransaction() session bookmarks = %v, want [other]", got)
+	}
+	if err := m.Commit(txID); err != nil {
+		t.Fatalf("manager.Commit() error = %v", err)
+	}
+	if got := other.Bookmarks(); !reflect.DeepEqual(got, []string{"bookmark"}) {
+		t.Errorf("BookmarkManager.Bookmarks() after commit = %v, want [bookmark]", got)
+	}
+}
+
+func Test_manager_DatabaseName(t *testing.T) {
+	m, driver := newFakeManager()
+
+	if _, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u"}); err != nil {
+		t.Fatalf("manager.Query() error = %v", err)
+	}
+	if _, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u", DatabaseName: "tenant1"}); err != nil {
+		t.Fatalf("manager.Query() in another database error = %v", err)
+	}
+	txID, err := m.BeginTransaction(TransactionParams{IsWrite: true, DatabaseName: "tenant2"})
+	if err != nil {
+		t.Fatalf("manager.BeginTransaction() in another database error = %v", err)
+	}
+
+	wantDatabases := []string{"neo4j", "tenant1", "tenant2"}
+	for i, session := range driver.sessions {
+		if session.config.DatabaseName != wantDatabases[i] {
+			t.Errorf("session %d database = %s, want %s", i, session.config.DatabaseName, wantDatabases[i])
+		}
+	}
+	if infos := m.Transactions(); len(infos) != 1 || infos[0].DatabaseName != "tenant2" {
+		t.Errorf("manager.Transactions() = %+v, want a transaction in tenant2", infos)
+	}
+
+	// A query cannot switch the database of its transaction
+	if _, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u", Transaction: txID, DatabaseName: "tenant2"}); err != nil {
+		t.Errorf("manager.Query() in the database of its transaction error = %v", err)
+	}
+	if _, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u", Transaction: txID, DatabaseName: "tenant1"}); !IsQueryError(err) {
+		t.Errorf("manager.Query() in another database than its transaction error = %v, want a Query error", err)
+	}
+	if err := m.Commit(txID); err != nil {
+		t.Errorf("manager.Commit() error = %v", err)
+	}
+}
+
+func Test_manager_TransactionConfig(t *testing.T) {
+	m, driver := newFakeManager()
+
+	if _, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u", Timeout: -time.Second}); !IsQueryError(err) {
+		t.Errorf("manager.Query() with a negative timeout error = %v, want a Query error", err)
+	}
+	if _, err := m.BeginTransaction(TransactionParams{Metadata: map[string]InputStruct{"app": nil}}); !IsTransactionError(err) {
+		t.Errorf("manager.BeginTransaction() with a nil metadata value error = %v, want a Transaction error", err)
+	}
+	if len(driver.sessions) != 0 {
+		t.Errorf("a wrong transaction config opened %d sessions, want 0", len(driver.sessions))
+	}
+}
+
+func Test_manager_Concurrency(t *testing.T) {
+	m, driver := newFakeManager()
+
+	const workers = 20
+	var wg sync.WaitGroup
+	var endErrors int32
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u"}); err != nil {
+				t.Errorf("manager.Query() error = %v", err)
+			}
+			_ = m.LastBookmark()
+
+			txID, err := m.BeginTransaction(TransactionParams{IsWrite: true})
+			if err != nil {
+				t.Errorf("manager.BeginTransaction() error = %v", err)
+				return
+			}
+
+			// Query the transaction and end it twice at the same time, only one end must succeed
+			var txWg sync.WaitGroup
+			txWg.Add(4)
+			go func() {
+				defer txWg.Done()
+				_, _ = m.Query(QueryParams{Query: "MATCH (u:User) RETURN u", Transaction: txID})
+			}()
+			go func() {
+				defer txWg.Done()
+				_ = m.Transactions()
+			}()
+			go func() {
+				defer txWg.Done()
+				if err := m.Commit(txID); err != nil {
+					atomic.AddInt32(&endErrors, 1)
+				}
+			}()
+			go func() {
+				defer txWg.Done()
+				if err := m.Rollback(txID); err != nil {
+					atomic.AddInt32(&endErrors, 1)
+				}
+			}()
+			txWg.Wait()
+		}()
+	}
+	wg.Wait()
+
+	if endErrors != workers {
+		t.Errorf("%d concurrent ends of the same transaction failed, want %d", endErrors, workers)
+	}
+	for _, session := range driver.sessions {
+		if session.closeCount != 1 {
+			t.Errorf("session closed %d times, want 1", session.closeCount)
+		}
+	}
+	if len(m.Transactions()) != 0 {
+		t.Errorf("manager.Transactions() = %v, want none", m.Transactions())
+	}
+	if err := m.Close(); err != nil {
+		t.Errorf("manager.Close() error = %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package neo4go
+
+import (
+	"context"
+
+	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
+)
+
+// QueryBatch runs every query in a single new transaction, and commits it only if all of them succeed
+func (m *manager) QueryBatch(queries []QueryParams, params TransactionParams) ([]QueryResult, Neo4GoError) {
+	return m.QueryBatchContext(context.Background(), queries, params)
+}
+
+// QueryBatchContext is the same as QueryBatch, but aborts the batch and rolls back its transaction if the context is done before its end
+func (m *manager) QueryBatchContext(ctx context.Context, queries []QueryParams, params TransactionParams) ([]QueryResult, Neo4GoError) {
+	results := make([]QueryResult, 0, len(queries))
+	if len(queries) == 0 {
+		return results, nil
+	}
+
+	// A shutdown waits for the whole batch, not only for its statements
+	if err := m.startOperation(true); err != nil {
+		return nil, err
+	}
+	defer m.endOperation()
+
+	tx, err := m.BeginContext(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	for index, queryParams := range queries {
+		result, queryErr := tx.QueryContext(ctx, queryParams)
+		if queryErr != nil {
+			// The transaction may have already been released if the query was aborted.
+			// The rollback must not depend on the context of the batch, which may be done already
+			_ = tx.RollbackContext(context.Background())
+
+			return nil, &internalErr.BatchError{
+				Err:   "A statement of the batch failed, so the whole batch was rolled back",
+				Index: index,
+				Cause: queryErr,
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	commitErr := tx.CommitContext(ctx)
+	if commitErr != nil {
+		if !IsContextError(commitErr) {
+			// A failed commit leaves the transaction in the manager store, so it must still be released
+			_ = tx.RollbackContext(context.Background())
+		}
+
+		return nil, commitErr
+	}
+
+	return results, nil
+}
@@ -0,0 +1,112 @@
+package neo4go
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_manager_QueryBatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		queries        []QueryParams
+		wantErrIndex   int
+		wantCommitted  bool
+		wantRolledBack bool
+		wantTxQueries  int
+	}{
+		{
+			name: "Should run and commit every statement",
+			queries: []QueryParams{
+				{Query: "CREATE (u:User)"},
+				{Query: "MATCH (u:User) RETURN u", ResultMode: STREAMED_RESULT},
+			},
+			wantErrIndex:  -1,
+			wantCommitted: true,
+			wantTxQueries: 2,
+		},
+		{
+			name: "Should roll back every statement when one fails",
+			queries: []QueryParams{
+				{Query: "CREATE (u:User)"},
+				{Query: "MATCH (u:User {id: $id}) RETURN u"},
+				{Query: "MATCH (u:User) RETURN u"},
+			},
+			wantErrIndex:   1,
+			wantRolledBack: true,
+			wantTxQueries:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, driver := newFakeManager()
+
+			results, err := m.QueryBatch(tt.queries, TransactionParams{IsWrite: true})
+			if tt.wantErrIndex >= 0 {
+				index, isBatchErr := BatchErrorIndex(err)
+				if !isBatchErr || index != tt.wantErrIndex {
+					t.Fatalf("manager.QueryBatch() error = %v, want a Batch error on statement %d", err, tt.wantErrIndex)
+				}
+				if cause := BatchErrorCause(err); !IsParameterError(cause) {
+					t.Errorf("BatchErrorCause() = %v, want a Parameter error", cause)
+				}
+			} else if err != nil {
+				t.Fatalf("manager.QueryBatch() error = %v", err)
+			} else if len(results) != len(tt.queries) {
+				t.Errorf("manager.QueryBatch() returned %d results, want %d", len(results), len(tt.queries))
+			}
+
+			if len(driver.sessions) != 1 {
+				t.Fatalf("manager.QueryBatch() opened %d sessions, want 1", len(driver.sessions))
+			}
+			fakeTx := driver.sessions[0].transactions[0]
+			if len(fakeTx.queries) != tt.wantTxQueries {
+				t.Errorf("transaction ran %d queries, want %d", len(fakeTx.queries), tt.wantTxQueries)
+			}
+			if fakeTx.committed != tt.wantCommitted || fakeTx.rolledBack != tt.wantRolledBack {
+				t.Errorf("transaction committed = %v / rolled back = %v, want %v / %v", fakeTx.committed, fakeTx.rolledBack, tt.wantCommitted, tt.wantRolledBack)
+			}
+			if len(m.Transactions()) != 0 {
+				t.Errorf("batch transaction is still stored in the manager")
+			}
+		})
+	}
+}
+
+func Test_manager_QueryBatch_Empty(t *testing.T) {
+	m, driver := newFakeManager()
+
+	results, err := m.QueryBatch(nil, TransactionParams{})
+	if err != nil || len(results) != 0 {
+		t.Errorf("manager.QueryBatch() = %v, %v, want no results and no error", results, err)
+	}
+	if len(driver.sessions) != 0 {
+		t.Errorf("manager.QueryBatch() opened a session for an empty batch")
+	}
+}
+
+func Test_manager_QueryBatch_DoneContext(t *testing.T) {
+	m, driver := newFakeManager()
+	driver.runDelay = 50 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := m.QueryBatchContext(ctx, []QueryParams{{Query: "CREATE (u:User)"}}, TransactionParams{IsWrite: true})
+	if index, isBatchErr := BatchErrorIndex(err); !isBatchErr || index != 0 || !IsContextError(BatchErrorCause(err)) {
+		t.Fatalf("manager.QueryBatchContext() error = %v, want a Batch error on statement 0 caused by the context", err)
+	}
+
+	// The aborted statement releases the transaction as soon as it returns
+	session := driver.sessions[0]
+	deadline := time.Now().Add(time.Second)
+	for !session.isClosed() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !session.isClosed() {
+		t.Errorf("manager.QueryBatchContext() did not close the session of the aborted batch")
+	}
+	if len(m.Transactions()) != 0 {
+		t.Errorf("batch transaction is still stored in the manager")
+	}
+}
@@ -31,6 +31,11 @@ type primitiveInputObject interface {
 	PrimitiveConvert() interface{}
 }
 
+// ConvertInput converts a query input into the raw value given to the neo4j-go-driver, as the manager does with the params of a query
+func ConvertInput(obj InputStruct) interface{} {
+	return convertInputObject(obj)
+}
+
 // convertInputObject takes an input object and converts it into an interface using firstly the most primitive object functions
 func convertInputObject(obj InputStruct) interface{} {
 	if obj == nil {
@@ -275,6 +280,166 @@ func (val *inputDateTime) PrimitiveConvert() interface{} {
 	}
 }
 
+// inputDate is an implementation of the primitiveInputObject for the neo4j Date type
+type inputDate struct {
+	Value *time.Time
+}
+
+// NewInputDate creates a primitiveInputObject from the date of the golang type Time, ignoring its clock and time zone
+func NewInputDate(value *time.Time) InputStruct {
+	return &inputDate{Value: value}
+}
+
+// ConvertToMap converts this input as a map of query inputs
+func (val *inputDate) ConvertToMap() map[string]InputStruct {
+	return nil
+}
+
+// ConvertToInputObject directly converts the object as an input object?
+func (val *inputDate) ConvertToInputObject() InputStruct {
+	return val
+}
+
+// PrimitiveConvert directly converts the object as an interface and
+// should not be used outside of this package to allow fully functionning type checking
+func (val *inputDate) PrimitiveConvert() interface{} {
+	if val.Value == nil {
+		return nil
+	}
+
+	return neo4j.DateOf(*val.Value)
+}
+
+// inputLocalTime is an implementation of the primitiveInputObject for the neo4j LocalTime type
+type inputLocalTime struct {
+	Value *time.Time
+}
+
+// NewInputLocalTime creates a primitiveInputObject from the local time of the golang type Time, ignoring its date and time zone
+func NewInputLocalTime(value *time.Time) InputStruct {
+	return &inputLocalTime{Value: value}
+}
+
+// ConvertToMap converts this input as a map of query inputs
+func (val *inputLocalTime) ConvertToMap() map[string]InputStruct {
+	return nil
+}
+
+// ConvertToInputObject directly converts the object as an input object?
+func (val *inputLocalTime) ConvertToInputObject() InputStruct {
+	return val
+}
+
+// PrimitiveConvert directly converts the object as an interface and
+// should not be used outside of this package to allow fully functionning type checking
+func (val *inputLocalTime) PrimitiveConvert() interface{} {
+	if val.Value == nil {
+		return nil
+	}
+
+	return neo4j.LocalTimeOf(*val.Value)
+}
+
+// inputOffsetTime is an implementation of the primitiveInputObject for the neo4j OffsetTime type
+type inputOffsetTime struct {
+	Value *time.Time
+}
+
+// NewInputOffsetTime creates a primitiveInputObject from the time of the golang type Time with the offset of its time zone, ignoring its date
+func NewInputOffsetTime(value *time.Time) InputStruct {
+	return &inputOffsetTime{Value: value}
+}
+
+// ConvertToMap converts this input as a map of query inputs
+func (val *inputOffsetTime) ConvertToMap() map[string]InputStruct {
+	return nil
+}
+
+// ConvertToInputObject directly converts the object as an input object?
+func (val *inputOffsetTime) ConvertToInputObject() InputStruct {
+	return val
+}
+
+// PrimitiveConvert directly converts the object as an interface and
+// should not be used outside of this package to allow fully functionning type checking
+func (val *inputOffsetTime) PrimitiveConvert() interface{} {
+	if val.Value == nil {
+		return nil
+	}
+
+	return neo4j.OffsetTimeOf(*val.Value)
+}
+
+// inputLocalDateTime is an implementation of the primitiveInputObject for the neo4j LocalDateTime type
+type inputLocalDateTime struct {
+	Value *time.Time
+}
+
+// NewInputLocalDateTime creates a primitiveInputObject from the date and time of the golang type Time, ignoring its time zone
+func NewInputLocalDateTime(value *time.Time) InputStruct {
+	return &inputLocalDateTime{Value: value}
+}
+
+// ConvertToMap converts this input as a map of query inputs
+func (val *inputLocalDateTime) ConvertToMap() map[string]InputStruct {
+	return nil
+}
+
+// ConvertToInputObject directly converts the object as an input object?
+func (val *inputLocalDateTime) ConvertToInputObject() InputStruct {
+	return val
+}
+
+// PrimitiveConvert directly converts the object as an interface and
+// should not be used outside of this package to allow fully functionning type checking
+func (val *inputLocalDateTime) PrimitiveConvert() interface{} {
+	if val.Value == nil {
+		return nil
+	}
+
+	return neo4j.LocalDateTimeOf(*val.Value)
+}
+
+// inputDuration is an implementation of the primitiveInputObject for the neo4j Duration type
+type inputDuration struct {
+	Value *neo4j.Duration
+}
+
+// NewInputDuration creates a primitiveInputObject from the golang type Duration, as a neo4j Duration of seconds and nanoseconds
+func NewInputDuration(value *time.Duration) InputStruct {
+	if value == nil {
+		return &inputDuration{}
+	}
+
+	duration := neo4j.DurationOf(0, 0, int64(*value/time.Second), int(*value%time.Second))
+	return &inputDuration{Value: &duration}
+}
+
+// NewInputNeo4jDuration creates a primitiveInputObject from the neo4j-go-driver type Duration, which can also hold months and days
+func NewInputNeo4jDuration(value *neo4j.Duration) InputStruct {
+	return &inputDuration{Value: value}
+}
+
+// ConvertToMap converts this input as a map of query inputs
+func (val *inputDuration) ConvertToMap() map[string]InputStruct {
+	return nil
+}
+
+// ConvertToInputObject directly converts the object as an input object?
+func (val *inputDuration) ConvertToInputObject() InputStruct {
+	return val
+}
+
+// PrimitiveConvert directly converts the object as an interface and
+// should not be used outside of this package to allow fully functionning type checking
+func (val *inputDuration) PrimitiveConvert() interface{} {
+	if val.Value == nil {
+		return nil
+	}
+
+	return *(val.Value)
+}
+
 // inputPoint is an implementation of the primitiveInputObject for the neo4j Point type
 type inputPoint struct {
 	Value *neo4j.Point
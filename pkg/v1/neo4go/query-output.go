@@ -3,6 +3,7 @@ package neo4go
 import (
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
 	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
@@ -653,6 +654,10 @@ type QueryResult interface {
 
 	// RawResult allow to retrieve the non-typed neo4j.Result
 	RawResult() neo4j.Result
+
+	// Close releases the session used by a streamed result. It is called automatically once all the records were read
+	// and does nothing for buffered results
+	Close() Neo4GoError
 }
 
 // Single returns one and only one record from the result stream. Any error passed in
@@ -662,6 +667,11 @@ type QueryResult interface {
 func Single(from QueryResult, err error) (*RecordMap, Neo4GoError) {
 	var record *RecordMap
 
+	// Release the session of a streamed result, even if it was not fully read
+	if from != nil {
+		defer from.Close()
+	}
+
 	if err != nil {
 		if convertedErr, canConvert := err.(Neo4GoError); canConvert {
 			return nil, convertedErr
@@ -693,6 +703,11 @@ func Single(from QueryResult, err error) (*RecordMap, Neo4GoError) {
 		}
 	}
 
+	// The stream may also fail after its last record
+	if err := from.Err(); err != nil {
+		return nil, toDriverError(err)
+	}
+
 	return record, nil
 }
 
@@ -703,6 +718,11 @@ func Single(from QueryResult, err error) (*RecordMap, Neo4GoError) {
 func Collect(from QueryResult, err error) ([]RecordMap, Neo4GoError) {
 	var list []RecordMap
 
+	// Release the session of a streamed result, even if it was not fully read
+	if from != nil {
+		defer from.Close()
+	}
+
 	if err != nil {
 		return nil, toDriverError(err)
 	}
@@ -804,11 +824,17 @@ type queryResult struct {
 
 	// The formatting to apply to the records of this result
 	flags queryOutputFlag
+
+	// The function releasing the resources of the result, or nil if there is nothing to release
+	closer func() error
+
+	// Ensures that the closer is only called once
+	closeOnce sync.Once
 }
 
-// newQueryResult creates a new instance of QueryResult, with a given raw neo4j query result.
-func newQueryResult(result neo4j.Result, flags queryOutputFlag) QueryResult {
-	return &queryResult{result: result, flags: flags}
+// newQueryResult creates a new instance of QueryResult, with a given raw neo4j query result and an optional closer function
+func newQueryResult(result neo4j.Result, flags queryOutputFlag, closer func() error) QueryResult {
+	return &queryResult{result: result, flags: flags, closer: closer}
 }
 
 // Keys returns the keys available on the result set.
@@ -823,7 +849,12 @@ func (res *queryResult) Keys() ([]string, Neo4GoError) {
 
 // Next returns true only if there is a record to be processed.
 func (res *queryResult) Next() bool {
-	return res.result.Next()
+	hasNext := res.result.Next()
+	if !hasNext {
+		_ = res.Close()
+	}
+
+	return hasNext
 }
 
 // Err returns the latest error that caused this Next to return false.
@@ -868,9 +899,13 @@ func (res *queryResult) Summary() (neo4j.ResultSummary, Neo4GoError) {
 // about the statement execution.
 func (res *queryResult) Consume() (neo4j.ResultSummary, Neo4GoError) {
 	sum, err := res.result.Consume()
+	closeErr := res.Close()
 	if err != nil {
 		return nil, toDriverError(err)
 	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
 
 	return sum, nil
 }
@@ -879,3 +914,20 @@ func (res *queryResult) Consume() (neo4j.ResultSummary, Neo4GoError) {
 func (res *queryResult) RawResult() neo4j.Result {
 	return res.result
 }
+
+// Close releases the session used by a streamed result. It is called automatically once all the records were read
+// and does nothing for buffered results
+func (res *queryResult) Close() Neo4GoError {
+	var err error
+	res.closeOnce.Do(func() {
+		if res.closer != nil {
+			err = res.closer()
+		}
+	})
+
+	if err != nil {
+		return toDriverError(err)
+	}
+
+	return nil
+}
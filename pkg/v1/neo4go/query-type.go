@@ -0,0 +1,178 @@
+package neo4go
+
+import "strings"
+
+// QueryType represents the kind of operation done by a cypher query
+type QueryType uint
+
+const (
+	// READ_QUERY only reads data
+	READ_QUERY QueryType = iota
+
+	// WRITE_QUERY creates, updates or deletes data
+	WRITE_QUERY
+
+	// SCHEMA_QUERY creates or drops indexes and constraints
+	SCHEMA_QUERY
+
+	// ADMIN_QUERY administrates the DBMS (databases, users, roles, privileges...)
+	ADMIN_QUERY
+
+	// PROCEDURE_CALL_QUERY calls a procedure, which may read or write data, without any write clause of its own.
+	// It only runs in a write session when the procedure is known to write
+	PROCEDURE_CALL_QUERY
+)
+
+// String returns the name of the query type
+func (queryType QueryType) String() string {
+	switch queryType {
+	case READ_QUERY:
+		return "read"
+	case WRITE_QUERY:
+		return "write"
+	case SCHEMA_QUERY:
+		return "schema"
+	case ADMIN_QUERY:
+		return "admin"
+	case PROCEDURE_CALL_QUERY:
+		return "procedure-call"
+	default:
+		return "unknown"
+	}
+}
+
+// The clauses that write data
+var cypherWriteClauses = map[string]bool{
+	"CREATE": true, "MERGE": true, "DELETE": true, "DETACH": true, "SET": true, "REMOVE": true, "FOREACH": true,
+}
+
+// The clauses that can only start an administration command
+var cypherAdminClauses = map[string]bool{
+	"SHOW": true, "ALTER": true, "RENAME": true, "GRANT": true, "REVOKE": true, "DENY": true, "START": true, "STOP": true,
+}
+
+// The prefixes of the names of the known procedures that write data, schema or security, in lower case.
+// The calls of the other procedures run in a read session, so the WRITE_ACCESS_MODE of the query params must be used for the ones that write
+var cypherWriteProcedurePrefixes = []string{
+	"db.create", "db.index.fulltext.createnodeindex", "db.index.fulltext.createrelationshipindex", "db.index.fulltext.drop", "db.clearquerycaches",
+	"dbms.setconfigvalue", "dbms.security.",
+	"apoc.create.", "apoc.merge.", "apoc.refactor.", "apoc.periodic.", "apoc.nodes.delete", "apoc.trigger.", "apoc.schema.assert", "apoc.atomic.",
+}
+
+// The words that may come between CREATE or DROP and the object they apply to, such as CREATE OR REPLACE DATABASE
+var cypherObjectModifiers = map[string]bool{
+	"OR": true, "REPLACE": true, "COMPOSITE": true,
+	"UNIQUE": true, "FULLTEXT": true, "BTREE": true, "LOOKUP": true, "TEXT": true, "POINT": true, "RANGE": true,
+}
+
+// ClassifyQuery determines the kind of operation done by a cypher query.
+// String literals, comments, escaped names, labels and property names are ignored,
+// so that a query such as MATCH (n) RETURN n.offset is a read query
+func ClassifyQuery(query string) QueryType {
+	queryType, _ := classifyCypherTokens(tokenizeCypher(query))
+	return queryType
+}
+
+// classifyCypherTokens determines the kind of operation done by a tokenized query, and whether it must run in a write session.
+// Administration commands only need a read session when they show information, and procedures only need a write session when they are known to write
+func classifyCypherTokens(tokens []cypherToken) (QueryType, bool) {
+	keywords := make([]string, 0)
+	callsWriteProcedure := false
+	for i, token := range tokens {
+		if token.kind == cypherKeyword {
+			keywords = append(keywords, token.text)
+			continue
+		}
+
+		// Procedure names follow CALL, while subqueries start with a brace
+		if token.kind == cypherName && i > 0 && tokens[i-1].kind == cypherKeyword && tokens[i-1].text == "CALL" {
+			keywords = append(keywords, "CALL PROCEDURE")
+			if isWriteProcedure(cypherProcedureName(tokens[i:])) {
+				callsWriteProcedure = true
+			}
+		}
+	}
+
+	keywords = skipCypherPrefixes(keywords)
+	if len(keywords) == 0 {
+		return READ_QUERY, false
+	}
+
+	if cypherAdminClauses[keywords[0]] {
+		return ADMIN_QUERY, keywords[0] != "SHOW"
+	}
+
+	if keywords[0] == "CREATE" || keywords[0] == "DROP" {
+		object := ""
+		for _, keyword := range keywords[1:] {
+			if !cypherObjectModifiers[keyword] {
+				object = keyword
+				break
+			}
+		}
+
+		switch object {
+		case "INDEX", "CONSTRAINT":
+			return SCHEMA_QUERY, true
+		case "DATABASE", "USER", "ROLE", "ALIAS":
+			return ADMIN_QUERY, true
+		}
+
+		// Unlike CREATE, DROP is never a clause of a data query
+		if keywords[0] == "DROP" {
+			return ADMIN_QUERY, true
+		}
+	}
+
+	isProcedureCall := false
+	for _, keyword := range keywords {
+		if cypherWriteClauses[keyword] {
+			return WRITE_QUERY, true
+		}
+		if keyword == "CALL PROCEDURE" {
+			isProcedureCall = true
+		}
+	}
+
+	if isProcedureCall {
+		return PROCEDURE_CALL_QUERY, callsWriteProcedure
+	}
+
+	return READ_QUERY, false
+}
+
+// cypherProcedureName returns the full name of the procedure starting at the first token, such as db.index.fulltext.queryNodes
+func cypherProcedureName(tokens []cypherToken) string {
+	parts := []string{tokens[0].text}
+	for i := 1; i+1 < len(tokens) && isCypherSymbol(&tokens[i], ".") && tokens[i+1].kind == cypherName; i += 2 {
+		parts = append(parts, tokens[i+1].text)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// isWriteProcedure tells if the procedure that has the given name is known to write
+func isWriteProcedure(name string) bool {
+	name = strings.ToLower(name)
+	for _, prefix := range cypherWriteProcedurePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// skipCypherPrefixes removes the keywords that come before the first clause of a query (EXPLAIN, PROFILE, CYPHER and USE)
+func skipCypherPrefixes(keywords []string) []string {
+	for len(keywords) > 0 {
+		switch keywords[0] {
+		case "EXPLAIN", "PROFILE", "CYPHER", "USE":
+			keywords = keywords[1:]
+		default:
+			return keywords
+		}
+	}
+
+	return keywords
+}
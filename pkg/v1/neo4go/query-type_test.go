@@ -0,0 +1,49 @@
+package neo4go
+
+import "testing"
+
+func TestClassifyQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		want      QueryType
+		wantWrite bool
+	}{
+		{name: "Should classify a match as read", query: "MATCH (n) RETURN n", want: READ_QUERY},
+		{name: "Should ignore properties containing write words", query: "MATCH (n) RETURN n.created_at, n.offset, n.set", want: READ_QUERY},
+		{name: "Should ignore string literals", query: "MATCH (n) WHERE n.name = 'set me' RETURN n", want: READ_QUERY},
+		{name: "Should ignore comments", query: "MATCH (n) // then CREATE something\nRETURN n", want: READ_QUERY},
+		{name: "Should ignore labels and relationship types", query: "MATCH (n:Create)-[:DELETE|SET]->(m) RETURN m", want: READ_QUERY},
+		{name: "Should ignore aliases and variables", query: "MATCH (set) RETURN set AS remove", want: READ_QUERY},
+		{name: "Should classify a create as write", query: "CREATE (n:User {name: $name})", want: WRITE_QUERY, wantWrite: true},
+		{name: "Should classify a lower case write", query: "match (n) set n.age = 1", want: WRITE_QUERY, wantWrite: true},
+		{name: "Should classify a merge with ON CREATE as write", query: "MERGE (n:User) ON CREATE SET n.new = true", want: WRITE_QUERY, wantWrite: true},
+		{name: "Should classify a detach delete as write", query: "MATCH (n) DETACH DELETE n", want: WRITE_QUERY, wantWrite: true},
+		{name: "Should classify a write inside a subquery", query: "MATCH (n) CALL { WITH n CREATE (m) } RETURN n", want: WRITE_QUERY, wantWrite: true},
+		{name: "Should classify a write inside a foreach", query: "FOREACH (x IN $list | CREATE (:Item {v: x}))", want: WRITE_QUERY, wantWrite: true},
+		{name: "Should classify an index creation as schema", query: "CREATE INDEX user_name FOR (u:User) ON (u.name)", want: SCHEMA_QUERY, wantWrite: true},
+		{name: "Should classify a fulltext index creation as schema", query: "CREATE FULLTEXT INDEX names FOR (n:User) ON EACH [n.name]", want: SCHEMA_QUERY, wantWrite: true},
+		{name: "Should classify a constraint drop as schema", query: "DROP CONSTRAINT user_id", want: SCHEMA_QUERY, wantWrite: true},
+		{name: "Should classify a database creation as admin", query: "CREATE OR REPLACE DATABASE movies", want: ADMIN_QUERY, wantWrite: true},
+		{name: "Should classify a grant as admin", query: "GRANT ROLE reader TO alice", want: ADMIN_QUERY, wantWrite: true},
+		{name: "Should classify a show as a read admin query", query: "USE system SHOW DATABASES", want: ADMIN_QUERY},
+		{name: "Should classify a read-only procedure call as read", query: "CALL db.labels() YIELD label RETURN label", want: PROCEDURE_CALL_QUERY},
+		{name: "Should classify a known write procedure call as write", query: "CALL apoc.create.node(['User'], {})", want: PROCEDURE_CALL_QUERY, wantWrite: true},
+		{name: "Should classify a known write procedure call after a read one as write", query: "CALL db.labels() YIELD label CALL db.index.fulltext.createNodeIndex('names', [label], ['name']) RETURN label", want: PROCEDURE_CALL_QUERY, wantWrite: true},
+		{name: "Should ignore the variables named like clauses in a list of expressions", query: "MATCH (n) WITH n, create RETURN n", want: READ_QUERY},
+		{name: "Should ignore the operands named like clauses", query: "MATCH (n) WITH n.a + set AS b RETURN b", want: READ_QUERY},
+		{name: "Should classify a read subquery as read", query: "CALL { MATCH (n) RETURN n } RETURN n", want: READ_QUERY},
+		{name: "Should skip the query prefixes", query: "EXPLAIN MATCH (n) DELETE n", want: WRITE_QUERY, wantWrite: true},
+		{name: "Should classify an empty query as read", query: "", want: READ_QUERY},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyQuery(tt.query); got != tt.want {
+				t.Errorf("ClassifyQuery() = %s, want %s", got, tt.want)
+			}
+			if got := IsWriteQuery(tt.query); got != tt.wantWrite {
+				t.Errorf("IsWriteQuery() = %v, want %v", got, tt.wantWrite)
+			}
+		})
+	}
+}
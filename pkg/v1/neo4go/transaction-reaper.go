@@ -0,0 +1,188 @@
+package neo4go
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// The bounds of the interval at which the expired transactions are searched
+const (
+	minReaperInterval = 10 * time.Millisecond
+	maxReaperInterval = time.Minute
+)
+
+// TransactionInfo represents the state of a transaction currently open in a manager
+type TransactionInfo struct {
+	// The ID under which the transaction is stored in the manager
+	ID string
+
+	// The time elapsed since the transaction began
+	Age time.Duration
+
+	// The time elapsed since the transaction last ran a query
+	IdleTime time.Duration
+
+	// The access mode of the session running the transaction
+	AccessMode neo4j.AccessMode
+
+	// The database in which the transaction runs
+	DatabaseName string
+
+	// The number of queries run in the transaction
+	QueryCount int
+
+	// The number of queries of the transaction that are still running
+	RunningQueries int
+}
+
+// info returns the current information of the transaction. The store mutex must be held by the caller
+func (txSession *transactionSession) info(txID string, now time.Time) TransactionInfo {
+	return TransactionInfo{
+		ID:             txID,
+		Age:            now.Sub(txSession.createdAt),
+		IdleTime:       now.Sub(txSession.lastUsedAt),
+		AccessMode:     txSession.accessMode,
+		DatabaseName:   txSession.databaseName,
+		QueryCount:     txSession.queryCount,
+		RunningQueries: txSession.runningQueries,
+	}
+}
+
+// Transactions returns the information of all the transactions currently open in the manager, from the oldest to the newest
+func (m *manager) Transactions() []TransactionInfo {
+	now := time.Now()
+
+	m.transactionSessionsMutex.RLock()
+	infos := make([]TransactionInfo, 0, len(m.transactionSessions))
+	for txID, txSession := range m.transactionSessions {
+		infos = append(infos, txSession.info(txID, now))
+	}
+	m.transactionSessionsMutex.RUnlock()
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Age > infos[j].Age
+	})
+
+	return infos
+}
+
+// acquireTransaction returns the transaction that has the given ID, after marking the start of one of its queries.
+// A transaction that is being committed or rolled back cannot run queries anymore
+func (m *manager) acquireTransaction(txID string) (*transactionSession, Neo4GoError) {
+	m.transactionSessionsMutex.Lock()
+	defer m.transactionSessionsMutex.Unlock()
+
+	txSession, exists := m.transactionSessions[txID]
+	if !exists {
+		return nil, &internalErr.TransactionError{
+			Err: "Trying to query with a non existing transaction",
+		}
+	}
+	if txSession.ending {
+		return nil, &internalErr.TransactionError{
+			Err: "Trying to query with a transaction that is being committed or rolled back",
+		}
+	}
+
+	txSession.markUsed(time.Now(), true)
+	return txSession, nil
+}
+
+// releaseTransaction marks the end of a query started with acquireTransaction
+func (m *manager) releaseTransaction(txSession *transactionSession) {
+	m.transactionSessionsMutex.Lock()
+	defer m.transactionSessionsMutex.Unlock()
+
+	txSession.markUsed(time.Now(), false)
+}
+
+// markUsed updates the usage of the transaction at the start or at the end of one of its queries.
+// The store mutex must be held by the caller
+func (txSession *transactionSession) markUsed(now time.Time, starting bool) {
+	txSession.lastUsedAt = now
+	if starting {
+		txSession.queryCount++
+		txSession.runningQueries++
+	} else {
+		txSession.runningQueries--
+	}
+}
+
+// reaperInterval returns the interval at which the expired transactions must be searched, depending on the manager options
+func (m *manager) reaperInterval() time.Duration {
+	shortestLimit := m.options.TransactionIdleTimeout
+	if shortestLimit <= 0 || (m.options.TransactionMaxAge > 0 && m.options.TransactionMaxAge < shortestLimit) {
+		shortestLimit = m.options.TransactionMaxAge
+	}
+
+	interval := shortestLimit / 4
+	if interval < minReaperInterval {
+		interval = minReaperInterval
+	} else if interval > maxReaperInterval {
+		interval = maxReaperInterval
+	}
+
+	return interval
+}
+
+// runReaper periodically rolls back the expired transactions until the stop channel is closed
+func (m *manager) runReaper(stop chan struct{}) {
+	ticker := time.NewTicker(m.reaperInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			m.reapTransactions(now)
+		}
+	}
+}
+
+// reapTransactions rolls back and removes from the store every transaction that is idle or old for too long.
+// Transactions that are running a query are never idle, but they are still reaped when too old. The ones being ended are left to their owner
+func (m *manager) reapTransactions(now time.Time) {
+	reaped := make(map[string]*transactionSession)
+	reapedInfos := make([]TransactionInfo, 0)
+
+	m.transactionSessionsMutex.Lock()
+	for txID, txSession := range m.transactionSessions {
+		if txSession.ending {
+			continue
+		}
+
+		info := txSession.info(txID, now)
+		isIdle := m.options.TransactionIdleTimeout > 0 && info.IdleTime >= m.options.TransactionIdleTimeout && txSession.runningQueries == 0
+		isOld := m.options.TransactionMaxAge > 0 && info.Age >= m.options.TransactionMaxAge
+		if isIdle || isOld {
+			reaped[txID] = txSession
+			reapedInfos = append(reapedInfos, info)
+			delete(m.transactionSessions, txID)
+		}
+	}
+	m.transactionSessionsMutex.Unlock()
+
+	// Release the transactions outside of the lock, as it requires round trips to the database.
+	// The driver cannot close a transaction while it runs a query, so a stuck transaction is released in background once its query returns
+	for _, info := range reapedInfos {
+		if info.RunningQueries > 0 {
+			go reaped[info.ID].release()
+		} else {
+			_ = reaped[info.ID].release()
+		}
+
+		if m.options.OnTransactionReaped != nil {
+			m.options.OnTransactionReaped(info)
+		} else {
+			log.Printf(
+				"Rolled back abandoned transaction %s (Age : %s / Idle : %s / Queries : %d)\n",
+				info.ID, info.Age, info.IdleTime, info.QueryCount,
+			)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package neo4go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+func Test_manager_reapTransactions(t *testing.T) {
+	now := time.Now()
+
+	type storedTx struct {
+		createdAt      time.Time
+		lastUsedAt     time.Time
+		runningQueries int
+	}
+	tests := []struct {
+		name       string
+		options    ManagerOptions
+		tx         storedTx
+		wantReaped bool
+	}{
+		{
+			name:    "Should reap an idle transaction",
+			options: ManagerOptions{TransactionIdleTimeout: time.Minute},
+			tx: storedTx{
+				createdAt:  now.Add(-2 * time.Minute),
+				lastUsedAt: now.Add(-2 * time.Minute),
+			},
+			wantReaped: true,
+		},
+		{
+			name:    "Should not reap a recently used transaction",
+			options: ManagerOptions{TransactionIdleTimeout: time.Minute},
+			tx: storedTx{
+				createdAt:  now.Add(-2 * time.Minute),
+				lastUsedAt: now.Add(-time.Second),
+			},
+			wantReaped: false,
+		},
+		{
+			name:    "Should reap a transaction older than the maximum age",
+			options: ManagerOptions{TransactionIdleTimeout: time.Minute, TransactionMaxAge: time.Hour},
+			tx: storedTx{
+				createdAt:  now.Add(-2 * time.Hour),
+				lastUsedAt: now.Add(-time.Second),
+			},
+			wantReaped: true,
+		},
+		{
+			name:    "Should not reap an idle transaction running a query",
+			options: ManagerOptions{TransactionIdleTimeout: time.Minute},
+			tx: storedTx{
+				createdAt:      now.Add(-2 * time.Hour),
+				lastUsedAt:     now.Add(-2 * time.Hour),
+				runningQueries: 1,
+			},
+			wantReaped: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &fakeTransaction{}
+			session := &fakeSession{}
+
+			var reapedInfo *TransactionInfo
+			tt.options.OnTransactionReaped = func(info TransactionInfo) {
+				reapedInfo = &info
+			}
+
+			m := &manager{
+				options: &tt.options,
+				transactionSessions: map[string]*transactionSession{
+					"tx": {
+						transaction:    tx,
+						session:        session,
+						accessMode:     neo4j.AccessModeWrite,
+						createdAt:      tt.tx.createdAt,
+						lastUsedAt:     tt.tx.lastUsedAt,
+						runningQueries: tt.tx.runningQueries,
+					},
+				},
+			}
+
+			m.reapTransactions(now)
+
+			_, stillStored := m.transactionSessions["tx"]
+			if stillStored == tt.wantReaped {
+				t.Errorf("manager.reapTransactions() kept transaction = %v, want %v", stillStored, !tt.wantReaped)
+			}
+			if tx.rolledBack != tt.wantReaped || session.closed != tt.wantReaped {
+				t.Errorf("manager.reapTransactions() released transaction = %v, want %v", tx.rolledBack && session.closed, tt.wantReaped)
+			}
+			if (reapedInfo != nil) != tt.wantReaped {
+				t.Errorf("manager.reapTransactions() reported transaction = %v, want %v", reapedInfo != nil, tt.wantReaped)
+			}
+		})
+	}
+}
+
+func Test_manager_reapTransactions_StuckQuery(t *testing.T) {
+	m, driver := newFakeManager()
+	m.options.TransactionMaxAge = time.Hour
+
+	reapedInfos := make(chan TransactionInfo, 1)
+	m.options.OnTransactionReaped = func(info TransactionInfo) {
+		reapedInfos <- info
+	}
+
+	txID, err := m.BeginTransaction(TransactionParams{IsWrite: true})
+	if err != nil {
+		t.Fatalf("manager.BeginTransaction() error = %v", err)
+	}
+	session := driver.sessions[0]
+	tx := session.transactions[0]
+	tx.runBlock = make(chan struct{})
+
+	queryDone := make(chan struct{})
+	go func() {
+		defer close(queryDone)
+		_, _ = m.Query(QueryParams{Query: "MATCH (u:User) RETURN u", Transaction: txID})
+	}()
+
+	// Wait for the query to be stuck in the transaction
+	for len(m.Transactions()) == 0 || m.Transactions()[0].RunningQueries == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	m.reapTransactions(time.Now().Add(2 * time.Hour))
+
+	if len(m.Transactions()) != 0 {
+		t.Errorf("manager.reapTransactions() kept the old transaction stuck in a query")
+	}
+	select {
+	case info := <-reapedInfos:
+		if info.ID != txID || info.RunningQueries != 1 {
+			t.Errorf("manager.reapTransactions() reported %+v, want transaction %s with 1 running query", info, txID)
+		}
+	default:
+		t.Errorf("manager.reapTransactions() did not report the old transaction stuck in a query")
+	}
+
+	// The transaction is released as soon as its query returns
+	close(tx.runBlock)
+	<-queryDone
+	deadline := time.Now().Add(time.Second)
+	for !session.isClosed() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !session.isClosed() {
+		t.Errorf("manager.reapTransactions() did not close the session once the stuck query returned")
+	}
+	if err := m.Commit(txID); !IsTransactionError(err) {
+		t.Errorf("manager.Commit() on a reaped transaction error = %v, want a Transaction error", err)
+	}
+}
+
+func Test_manager_Transactions(t *testing.T) {
+	now := time.Now()
+	m := &manager{
+		options: &ManagerOptions{},
+		transactionSessions: map[string]*transactionSession{
+			"recent": {
+				accessMode: neo4j.AccessModeRead,
+				createdAt:  now.Add(-time.Second),
+				lastUsedAt: now.Add(-time.Second),
+			},
+			"old": {
+				accessMode: neo4j.AccessModeWrite,
+				createdAt:  now.Add(-time.Hour),
+				lastUsedAt: now.Add(-time.Minute),
+				queryCount: 3,
+			},
+		},
+	}
+
+	infos := m.Transactions()
+	if len(infos) != 2 {
+		t.Fatalf("manager.Transactions() returned %d transactions, want 2", len(infos))
+	}
+	if infos[0].ID != "old" || infos[1].ID != "recent" {
+		t.Errorf("manager.Transactions() = [%s %s], want oldest first", infos[0].ID, infos[1].ID)
+	}
+	if infos[0].AccessMode != neo4j.AccessModeWrite || infos[0].QueryCount != 3 {
+		t.Errorf("manager.Transactions() = %+v, want write mode with 3 queries", infos[0])
+	}
+	if infos[0].Age < time.Hour || infos[0].IdleTime < time.Minute {
+		t.Errorf("manager.Transactions() = %+v, want an age of at least 1h and an idle time of at least 1m", infos[0])
+	}
+}
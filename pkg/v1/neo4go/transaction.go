@@ -0,0 +1,98 @@
+package neo4go
+
+import (
+	"context"
+
+	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
+)
+
+// Tx is a handle on a transaction stored in a manager.
+// Its queries always run inside the transaction, ignoring their Transaction and CommitOnSuccess params
+type Tx interface {
+	Querier
+
+	// ID returns the ID under which the transaction is stored in its manager
+	ID() string
+
+	// Commit commits this transaction and closes its session
+	Commit() Neo4GoError
+
+	// CommitContext is the same as Commit, but stops waiting for the commit if the context is done before its end
+	CommitContext(context.Context) Neo4GoError
+
+	// Rollback rolls back this transaction and closes its session
+	Rollback() Neo4GoError
+
+	// RollbackContext is the same as Rollback, but stops waiting for the rollback if the context is done before its end
+	RollbackContext(context.Context) Neo4GoError
+}
+
+// managerTx is the default implementation of the Tx interface
+type managerTx struct {
+	// The manager in which the transaction is stored
+	manager *manager
+
+	// The ID of the transaction in the manager store
+	id string
+
+	// The context applied to every query of the transaction when no other context is given
+	ctx context.Context
+
+	// Tells if the transaction is managed by the manager (ReadTransaction and WriteTransaction), so that it cannot be ended by hand
+	managed bool
+}
+
+// newManagerTx creates a new instance of Tx, bound to a transaction of the given manager
+func newManagerTx(ctx context.Context, m *manager, txID string) *managerTx {
+	return &managerTx{manager: m, id: txID, ctx: ctx}
+}
+
+// ID returns the ID under which the transaction is stored in its manager
+func (tx *managerTx) ID() string {
+	return tx.id
+}
+
+// Query runs a single query inside this transaction. The Transaction and CommitOnSuccess params are ignored
+func (tx *managerTx) Query(queryParams QueryParams) (QueryResult, Neo4GoError) {
+	return tx.QueryContext(tx.ctx, queryParams)
+}
+
+// QueryContext is the same as Query, but aborts the query and rolls back the transaction if the context is done before the end of the query
+func (tx *managerTx) QueryContext(ctx context.Context, queryParams QueryParams) (QueryResult, Neo4GoError) {
+	queryParams.Transaction = tx.id
+	queryParams.CommitOnSuccess = false
+
+	return tx.manager.QueryContext(ctx, queryParams)
+}
+
+// Commit commits this transaction and closes its session
+func (tx *managerTx) Commit() Neo4GoError {
+	return tx.CommitContext(tx.ctx)
+}
+
+// CommitContext is the same as Commit, but stops waiting for the commit if the context is done before its end
+func (tx *managerTx) CommitContext(ctx context.Context) Neo4GoError {
+	if tx.managed {
+		return &internalErr.TransactionError{
+			Err: "Trying to commit a managed transaction, which is committed when its work returns nil",
+		}
+	}
+
+	return tx.manager.CommitContext(ctx, tx.id)
+}
+
+// Rollback rolls back this transaction and closes its session
+func (tx *managerTx) Rollback() Neo4GoError {
+	return tx.RollbackContext(tx.ctx)
+}
+
+// RollbackContext is the same as Rollback, but stops waiting for the rollback if the context is done before its end
+func (tx *managerTx) RollbackContext(ctx context.Context) Neo4GoError {
+	if tx.managed {
+		return &internalErr.TransactionError{
+			Err: "Trying to roll back a managed transaction, which is rolled back when its work returns an error",
+		}
+	}
+
+	return tx.manager.RollbackContext(ctx, tx.id)
+}
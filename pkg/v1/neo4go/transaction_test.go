@@ -0,0 +1,120 @@
+package neo4go
+
+import (
+	"testing"
+)
+
+func Test_managerTx(t *testing.T) {
+	tests := []struct {
+		name           string
+		end            func(Tx) Neo4GoError
+		wantCommitted  bool
+		wantRolledBack bool
+	}{
+		{
+			name:          "Should commit the transaction",
+			end:           func(tx Tx) Neo4GoError { return tx.Commit() },
+			wantCommitted: true,
+		},
+		{
+			name:           "Should roll back the transaction",
+			end:            func(tx Tx) Neo4GoError { return tx.Rollback() },
+			wantRolledBack: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, driver := newFakeManager()
+
+			tx, err := m.Begin(TransactionParams{IsWrite: true})
+			if err != nil {
+				t.Fatalf("manager.Begin() error = %v", err)
+			}
+			if _, err := tx.Query(QueryParams{Query: "CREATE (u:User)", Transaction: "ignored", CommitOnSuccess: true}); err != nil {
+				t.Fatalf("Tx.Query() error = %v", err)
+			}
+
+			// The string ID of the handle still works with the manager
+			if _, err := m.Query(QueryParams{Query: "MATCH (u:User) RETURN u", Transaction: tx.ID()}); err != nil {
+				t.Fatalf("manager.Query() with the ID of the handle error = %v", err)
+			}
+
+			if err := tt.end(tx); err != nil {
+				t.Fatalf("ending the transaction error = %v", err)
+			}
+
+			fakeTx := driver.sessions[0].transactions[0]
+			if len(fakeTx.queries) != 2 {
+				t.Errorf("transaction ran %d queries, want 2", len(fakeTx.queries))
+			}
+			if fakeTx.committed != tt.wantCommitted || fakeTx.rolledBack != tt.wantRolledBack {
+				t.Errorf("transaction committed = %v / rolled back = %v, want %v / %v", fakeTx.committed, fakeTx.rolledBack, tt.wantCommitted, tt.wantRolledBack)
+			}
+			if err := tx.Commit(); !IsTransactionError(err) {
+				t.Errorf("Tx.Commit() on an ended transaction error = %v, want a Transaction error", err)
+			}
+		})
+	}
+}
+
+func Test_managerTx_Managed(t *testing.T) {
+	m, _ := newFakeManager()
+
+	err := m.WriteTransaction(TransactionParams{}, func(tx Tx) error {
+		if err := tx.Commit(); !IsTransactionError(err) {
+			t.Errorf("Tx.Commit() in a managed transaction error = %v, want a Transaction error", err)
+		}
+		if err := tx.Rollback(); !IsTransactionError(err) {
+			t.Errorf("Tx.Rollback() in a managed transaction error = %v, want a Transaction error", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("manager.WriteTransaction() error = %v", err)
+	}
+}
+
+// createUser is a repository function that does not know if it runs inside a transaction
+func createUser(querier Querier) Neo4GoError {
+	_, err := querier.Query(QueryParams{Query: "CREATE (u:User)"})
+	return err
+}
+
+func Test_Querier(t *testing.T) {
+	tests := []struct {
+		name            string
+		querier         func(*manager) (Querier, Neo4GoError)
+		wantTransaction bool
+	}{
+		{
+			name:    "Should run the query in its own session with the manager",
+			querier: func(m *manager) (Querier, Neo4GoError) { return m, nil },
+		},
+		{
+			name:            "Should run the query in the transaction with a transaction handle",
+			querier:         func(m *manager) (Querier, Neo4GoError) { return m.Begin(TransactionParams{IsWrite: true}) },
+			wantTransaction: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, driver := newFakeManager()
+
+			querier, err := tt.querier(m)
+			if err != nil {
+				t.Fatalf("creating the querier error = %v", err)
+			}
+			if err := createUser(querier); err != nil {
+				t.Fatalf("createUser() error = %v", err)
+			}
+
+			session := driver.sessions[0]
+			if gotTransaction := len(session.transactions) == 1 && len(session.transactions[0].queries) == 1; gotTransaction != tt.wantTransaction {
+				t.Errorf("query ran in a transaction = %v, want %v", gotTransaction, tt.wantTransaction)
+			}
+			if gotAutoCommit := len(session.queries) == 1; gotAutoCommit == tt.wantTransaction {
+				t.Errorf("query ran in an auto-commit session = %v, want %v", gotAutoCommit, !tt.wantTransaction)
+			}
+		})
+	}
+}
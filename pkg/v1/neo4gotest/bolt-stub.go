@@ -0,0 +1,611 @@
+package neo4gotest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/UlysseGuyon/neo4go/pkg/v1/neo4go"
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// The identification of the Bolt protocol, sent by every client when connecting
+var boltMagic = []byte{0x60, 0x60, 0xB0, 0x17}
+
+// The only version of the Bolt protocol spoken by the stub server
+const boltVersion = 4
+
+// The query run by the driver to verify the connectivity to the database
+const connectivityQuery = "RETURN 1"
+
+// The beginning of the procedure call run by the driver to fetch the routing table of a cluster
+const routingTablePrefix = "CALL dbms.routing.getRoutingTable("
+
+// The time to live of the routing tables served by the stub server, in seconds
+const routingTableTTL = 300
+
+// BoltMessageType represents the type of a message sent by a client to a Bolt server
+type BoltMessageType byte
+
+// All the Bolt messages that a client can send
+const (
+	BoltHello    BoltMessageType = 0x01
+	BoltGoodbye  BoltMessageType = 0x02
+	BoltReset    BoltMessageType = 0x0F
+	BoltRun      BoltMessageType = 0x10
+	BoltBegin    BoltMessageType = 0x11
+	BoltCommit   BoltMessageType = 0x12
+	BoltRollback BoltMessageType = 0x13
+	BoltDiscard  BoltMessageType = 0x2F
+	BoltPull     BoltMessageType = 0x3F
+)
+
+// All the Bolt messages that a server can send
+const (
+	boltSuccess byte = 0x70
+	boltRecord  byte = 0x71
+	boltIgnored byte = 0x7E
+	boltFailure byte = 0x7F
+)
+
+// String returns the name of the message type
+func (messageType BoltMessageType) String() string {
+	switch messageType {
+	case BoltHello:
+		return "HELLO"
+	case BoltGoodbye:
+		return "GOODBYE"
+	case BoltReset:
+		return "RESET"
+	case BoltRun:
+		return "RUN"
+	case BoltBegin:
+		return "BEGIN"
+	case BoltCommit:
+		return "COMMIT"
+	case BoltRollback:
+		return "ROLLBACK"
+	case BoltDiscard:
+		return "DISCARD"
+	case BoltPull:
+		return "PULL"
+	default:
+		return fmt.Sprintf("UNKNOWN(0x%X)", byte(messageType))
+	}
+}
+
+// BoltMessage represents a message received by a stub server
+type BoltMessage struct {
+	// The type of the message
+	Type BoltMessageType
+
+	// The raw fields of the message
+	Fields []interface{}
+}
+
+// Query returns the query of a RUN message, or an empty string for any other message
+func (message BoltMessage) Query() string {
+	if message.Type != BoltRun || len(message.Fields) < 1 {
+		return ""
+	}
+
+	query, _ := message.Fields[0].(string)
+	return query
+}
+
+// Params returns the parameters of a RUN message, or nil for any other message
+func (message BoltMessage) Params() map[string]interface{} {
+	if message.Type != BoltRun || len(message.Fields) < 2 {
+		return nil
+	}
+
+	params, _ := message.Fields[1].(map[string]interface{})
+	return params
+}
+
+// Metadata returns the metadata of a RUN or BEGIN message (mode, bookmarks, tx_timeout, tx_metadata, db),
+// or nil for any other message. Inside an explicit transaction, the metadata is only sent with BEGIN
+func (message BoltMessage) Metadata() map[string]interface{} {
+	metaIndex := -1
+	switch message.Type {
+	case BoltRun:
+		metaIndex = 2
+	case BoltBegin:
+		metaIndex = 0
+	}
+
+	if metaIndex < 0 || len(message.Fields) <= metaIndex {
+		return nil
+	}
+
+	meta, _ := message.Fields[metaIndex].(map[string]interface{})
+	return meta
+}
+
+// String returns a readable representation of the message
+func (message BoltMessage) String() string {
+	if message.Type == BoltRun {
+		return fmt.Sprintf("%s %q", message.Type, message.Query())
+	}
+
+	return message.Type.String()
+}
+
+// BoltSuccess creates a SUCCESS response with the given metadata
+func BoltSuccess(meta map[string]interface{}) BoltStruct {
+	if meta == nil {
+		meta = map[string]interface{}{}
+	}
+
+	return BoltStruct{Tag: boltSuccess, Fields: []interface{}{meta}}
+}
+
+// BoltRecord creates a RECORD response with the given values, in the order of the fields of the result
+func BoltRecord(values ...interface{}) BoltStruct {
+	if values == nil {
+		values = []interface{}{}
+	}
+
+	return BoltStruct{Tag: boltRecord, Fields: []interface{}{values}}
+}
+
+// BoltFailure creates a FAILURE response with the given Neo4j status code (for example Neo.ClientError.Statement.SyntaxError)
+func BoltFailure(code string, message string) BoltStruct {
+	return BoltStruct{Tag: boltFailure, Fields: []interface{}{map[string]interface{}{"code": code, "message": message}}}
+}
+
+// BoltIgnored creates an IGNORED response
+func BoltIgnored() BoltStruct {
+	return BoltStruct{Tag: boltIgnored}
+}
+
+// BoltExchange represents a message expected by a stub server, with the responses it sends back
+type BoltExchange struct {
+	// The type of the expected message
+	Message BoltMessageType
+
+	// The exact query expected in a RUN message. An empty query matches any query
+	Query string
+
+	// The responses sent back, in order
+	Responses []BoltStruct
+}
+
+// ExpectBegin returns the exchange of a BEGIN message that succeeds
+func ExpectBegin() BoltExchange {
+	return BoltExchange{Message: BoltBegin, Responses: []BoltStruct{BoltSuccess(nil)}}
+}
+
+// ExpectRun returns the exchange of a RUN message with the given query, whose result has the given fields
+func ExpectRun(query string, fields ...string) BoltExchange {
+	rawFields := make([]interface{}, 0, len(fields))
+	for _, field := range fields {
+		rawFields = append(rawFields, field)
+	}
+
+	return BoltExchange{
+		Message:   BoltRun,
+		Query:     query,
+		Responses: []BoltStruct{BoltSuccess(map[string]interface{}{"fields": rawFields, "t_first": int64(0)})},
+	}
+}
+
+// ExpectPull returns the exchange of a PULL message that streams the given records, then the summary of a read query
+func ExpectPull(records ...[]interface{}) BoltExchange {
+	responses := make([]BoltStruct, 0, len(records)+1)
+	for _, record := range records {
+		responses = append(responses, BoltRecord(record...))
+	}
+	responses = append(responses, BoltSuccess(map[string]interface{}{"type": "r", "t_last": int64(0)}))
+
+	return BoltExchange{Message: BoltPull, Responses: responses}
+}
+
+// ExpectCommit returns the exchange of a COMMIT message that succeeds with the given bookmark, if not empty
+func ExpectCommit(bookmark string) BoltExchange {
+	return BoltExchange{Message: BoltCommit, Responses: []BoltStruct{BoltSuccess(nil)}}.WithBookmark(bookmark)
+}
+
+// ExpectRollback returns the exchange of a ROLLBACK message that succeeds
+func ExpectRollback() BoltExchange {
+	return BoltExchange{Message: BoltRollback, Responses: []BoltStruct{BoltSuccess(nil)}}
+}
+
+// WithBookmark returns a copy of the exchange whose last SUCCESS response carries the given bookmark.
+// An empty bookmark leaves the exchange unchanged
+func (exchange BoltExchange) WithBookmark(bookmark string) BoltExchange {
+	if bookmark == "" {
+		return exchange
+	}
+
+	responses := make([]BoltStruct, len(exchange.Responses))
+	copy(responses, exchange.Responses)
+
+	for i := len(responses) - 1; i >= 0; i-- {
+		if responses[i].Tag != boltSuccess || len(responses[i].Fields) < 1 {
+			continue
+		}
+
+		meta := map[string]interface{}{"bookmark": bookmark}
+		if oldMeta, isMap := responses[i].Fields[0].(map[string]interface{}); isMap {
+			for key, value := range oldMeta {
+				if key != "bookmark" {
+					meta[key] = value
+				}
+			}
+		}
+		responses[i] = BoltSuccess(meta)
+		break
+	}
+
+	exchange.Responses = responses
+	return exchange
+}
+
+// WillFail returns a copy of the exchange that answers with a FAILURE of the given Neo4j status code instead of its responses
+func (exchange BoltExchange) WillFail(code string, message string) BoltExchange {
+	exchange.Responses = []BoltStruct{BoltFailure(code, message)}
+	return exchange
+}
+
+// matches tells if a received message is the one expected by the exchange
+func (exchange BoltExchange) matches(message BoltMessage) bool {
+	if exchange.Message != message.Type {
+		return false
+	}
+
+	return exchange.Query == "" || exchange.Query == message.Query()
+}
+
+// String returns a readable representation of the expected message
+func (exchange BoltExchange) String() string {
+	return BoltMessage{Type: exchange.Message, Fields: []interface{}{exchange.Query}}.String()
+}
+
+// StubServer is a Bolt server listening on a loopback port, that replays a script of expected messages with scripted responses.
+// It lets the real neo4j-go-driver, and so a real neo4go manager, run end to end without any Neo4j instance.
+// HELLO, RESET and GOODBYE messages are handled automatically, as well as the connectivity checks of the driver when they are not scripted,
+// and the routing table fetches when the server routes a cluster.
+// After a FAILURE response, every message is IGNORED until the client sends a RESET, like a real server does
+type StubServer struct {
+	// The test that uses this server
+	t testing.TB
+
+	// The loopback listener accepting the connections of the driver
+	listener net.Listener
+
+	// The exchanges that were not played yet, in order
+	script []BoltExchange
+
+	// All the messages received by the server, in order
+	received []BoltMessage
+
+	// The connections currently open, to be closed with the server
+	connections map[net.Conn]struct{}
+
+	// The number of connections accepted since the start of the server
+	connectionCount int
+
+	// The routing table served to the driver, as the servers field of the routing procedure. Nil if the server does not route
+	routingTable []interface{}
+
+	// Tells if the server was closed
+	closed bool
+
+	// The mutex used to prevent concurrent access to the script and the connections
+	mutex sync.Mutex
+
+	// Waits for all the goroutines of the server to end
+	wg sync.WaitGroup
+}
+
+// NewStubServer starts a new stub server on a loopback port, with the given script.
+// At the end of the test, the server is closed and the test fails if any exchange was not played
+func NewStubServer(t testing.TB, script ...BoltExchange) *StubServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Could not start the stub server : %s", err)
+	}
+
+	server := &StubServer{
+		t:           t,
+		listener:    listener,
+		script:      script,
+		connections: make(map[net.Conn]struct{}),
+	}
+
+	server.wg.Add(1)
+	go server.acceptConnections()
+
+	t.Cleanup(func() {
+		server.Close()
+		server.AssertExpectations()
+	})
+
+	return server
+}
+
+// URI returns the bolt URI to use to connect to the server
+func (server *StubServer) URI() string {
+	return "bolt://" + server.listener.Addr().String()
+}
+
+// ManagerOptions returns the options of a manager connecting to the server.
+// The stub server does not support TLS, so the encryption of the driver is disabled
+func (server *StubServer) ManagerOptions() neo4go.ManagerOptions {
+	return neo4go.ManagerOptions{
+		URI:          server.URI(),
+		DatabaseName: "neo4j",
+		Configurers: []func(*neo4j.Config){
+			func(config *neo4j.Config) {
+				config.Encrypted = false
+			},
+		},
+	}
+}
+
+// RoutingURI returns the neo4j URI to use to connect to the server as the router of a cluster
+func (server *StubServer) RoutingURI() string {
+	return "neo4j://" + server.listener.Addr().String()
+}
+
+// Route makes the server act as the router of a cluster made of the given readers and writers.
+// The routing table is served automatically to the driver, so that a manager using the RoutingURI sends
+// its read sessions to the readers and its write sessions to the writers
+func (server *StubServer) Route(readers []*StubServer, writers []*StubServer) {
+	serverAddresses := func(servers []*StubServer) []interface{} {
+		addresses := make([]interface{}, 0, len(servers))
+		for _, s := range servers {
+			addresses = append(addresses, s.listener.Addr().String())
+		}
+		return addresses
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.routingTable = []interface{}{
+		map[string]interface{}{"role": "ROUTE", "addresses": serverAddresses([]*StubServer{server})},
+		map[string]interface{}{"role": "READ", "addresses": serverAddresses(readers)},
+		map[string]interface{}{"role": "WRITE", "addresses": serverAddresses(writers)},
+	}
+}
+
+// Expect adds exchanges at the end of the script
+func (server *StubServer) Expect(exchanges ...BoltExchange) {
+	server.mutex.Lock()
+	server.script = append(server.script, exchanges...)
+	server.mutex.Unlock()
+}
+
+// Received returns all the messages received by the server so far, in order
+func (server *StubServer) Received() []BoltMessage {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	received := make([]BoltMessage, len(server.received))
+	copy(received, server.received)
+
+	return received
+}
+
+// ExpectationsWereMet returns an error listing the exchanges of the script that were not played
+func (server *StubServer) ExpectationsWereMet() error {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if len(server.script) == 0 {
+		return nil
+	}
+
+	remaining := make([]string, 0, len(server.script))
+	for _, exchange := range server.script {
+		remaining = append(remaining, exchange.String())
+	}
+
+	return fmt.Errorf("%d expected Bolt messages were not received : %s", len(remaining), strings.Join(remaining, ", "))
+}
+
+// AssertExpectations fails the test if any exchange of the script was not played
+func (server *StubServer) AssertExpectations() {
+	server.t.Helper()
+
+	if err := server.ExpectationsWereMet(); err != nil {
+		server.t.Error(err)
+	}
+}
+
+// Close stops the server and closes all its connections
+func (server *StubServer) Close() {
+	server.mutex.Lock()
+	if server.closed {
+		server.mutex.Unlock()
+		return
+	}
+	server.closed = true
+
+	_ = server.listener.Close()
+	for conn := range server.connections {
+		_ = conn.Close()
+	}
+	server.mutex.Unlock()
+
+	server.wg.Wait()
+}
+
+// acceptConnections serves every connection of the listener until it is closed
+func (server *StubServer) acceptConnections() {
+	defer server.wg.Done()
+
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		server.mutex.Lock()
+		if server.closed {
+			server.mutex.Unlock()
+			_ = conn.Close()
+			return
+		}
+		server.connections[conn] = struct{}{}
+		server.connectionCount++
+		connectionID := fmt.Sprintf("bolt-%d", server.connectionCount)
+		server.wg.Add(1)
+		server.mutex.Unlock()
+
+		go server.serveConnection(conn, connectionID)
+	}
+}
+
+// stubConnection represents the state of a client connection to a stub server
+type stubConnection struct {
+	// The network connection to the client
+	conn net.Conn
+
+	// The ID of the connection, sent to the client in the HELLO response
+	id string
+
+	// Tells if a FAILURE was sent and no RESET was received since
+	failed bool
+
+	// Tells if the last RUN was an automatic connectivity check, whose PULL must also be answered automatically
+	checkingConnectivity bool
+
+	// Tells if the last RUN was an automatic routing table fetch, whose PULL must also be answered automatically
+	fetchingRoutingTable bool
+}
+
+// serveConnection runs the handshake then answers the messages of a client until the connection is closed
+func (server *StubServer) serveConnection(conn net.Conn, connectionID string) {
+	defer server.wg.Done()
+	defer func() {
+		server.mutex.Lock()
+		delete(server.connections, conn)
+		server.mutex.Unlock()
+		_ = conn.Close()
+	}()
+
+	if err := handshake(conn); err != nil {
+		return
+	}
+
+	client := &stubConnection{conn: conn, id: connectionID}
+	for {
+		rawMessage, err := readMessage(conn)
+		if err != nil {
+			return
+		}
+
+		message := BoltMessage{Type: BoltMessageType(rawMessage.Tag), Fields: rawMessage.Fields}
+		if message.Type == BoltGoodbye {
+			server.record(message)
+			return
+		}
+
+		for _, response := range server.respond(client, message) {
+			if err := writeMessage(conn, response); err != nil {
+				return
+			}
+			if response.Tag == boltFailure {
+				client.failed = true
+			}
+		}
+	}
+}
+
+// handshake checks the identification of the client and agrees on the version of the protocol
+func handshake(conn net.Conn) error {
+	request := make([]byte, 20)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(request[:4], boltMagic) {
+		return fmt.Errorf("the client is not speaking Bolt")
+	}
+
+	// The client proposes 4 versions, and the server answers with the one it chooses, or 0 if none is supported
+	for i := 0; i < 4; i++ {
+		version := binary.BigEndian.Uint32(request[4+4*i : 8+4*i])
+		if version&0xFF == boltVersion {
+			_, err := conn.Write([]byte{0x00, 0x00, 0x00, boltVersion})
+			return err
+		}
+	}
+
+	_, _ = conn.Write([]byte{0x00, 0x00, 0x00, 0x00})
+	return fmt.Errorf("the client does not support Bolt %d", boltVersion)
+}
+
+// record stores a received message
+func (server *StubServer) record(message BoltMessage) {
+	server.mutex.Lock()
+	server.received = append(server.received, message)
+	server.mutex.Unlock()
+}
+
+// respond returns the responses to a message, either automatic or scripted
+func (server *StubServer) respond(client *stubConnection, message BoltMessage) []BoltStruct {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.received = append(server.received, message)
+
+	// Handle the connection management messages automatically
+	switch {
+	case message.Type == BoltHello:
+		return []BoltStruct{BoltSuccess(map[string]interface{}{"server": "Neo4j/4.0.0", "connection_id": client.id})}
+	case message.Type == BoltReset:
+		client.failed = false
+		client.checkingConnectivity = false
+		client.fetchingRoutingTable = false
+		return []BoltStruct{BoltSuccess(nil)}
+	case client.failed:
+		return []BoltStruct{BoltIgnored()}
+	}
+
+	// Answer the routing table fetches of the driver if the server routes
+	if client.fetchingRoutingTable && message.Type == BoltPull {
+		client.fetchingRoutingTable = false
+		return []BoltStruct{BoltRecord(int64(routingTableTTL), server.routingTable), BoltSuccess(map[string]interface{}{"type": "r"})}
+	}
+	if message.Type == BoltRun && server.routingTable != nil && strings.HasPrefix(message.Query(), routingTablePrefix) {
+		client.fetchingRoutingTable = true
+		return []BoltStruct{BoltSuccess(map[string]interface{}{"fields": []interface{}{"ttl", "servers"}})}
+	}
+
+	// Answer the connectivity checks of the driver, unless they are part of the script
+	if client.checkingConnectivity && message.Type == BoltPull {
+		client.checkingConnectivity = false
+		return []BoltStruct{BoltRecord(int64(1)), BoltSuccess(map[string]interface{}{"type": "r"})}
+	}
+	if message.Type == BoltRun && message.Query() == connectivityQuery &&
+		(len(server.script) == 0 || !server.script[0].matches(message)) {
+		client.checkingConnectivity = true
+		return []BoltStruct{BoltSuccess(map[string]interface{}{"fields": []interface{}{"1"}})}
+	}
+
+	if len(server.script) == 0 || !server.script[0].matches(message) {
+		expected := "nothing"
+		if len(server.script) > 0 {
+			expected = server.script[0].String()
+		}
+		server.t.Errorf("Unexpected Bolt message %s, expected %s", message, expected)
+
+		return []BoltStruct{BoltFailure("Neo.ClientError.Request.Invalid", fmt.Sprintf("neo4gotest : unexpected message %s", message))}
+	}
+
+	exchange := server.script[0]
+	server.script = server.script[1:]
+
+	return exchange.Responses
+}
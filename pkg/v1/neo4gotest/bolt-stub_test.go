@@ -0,0 +1,312 @@
+package neo4gotest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/UlysseGuyon/neo4go/pkg/v1/neo4go"
+)
+
+func newStubManager(t *testing.T, options neo4go.ManagerOptions) neo4go.Manager {
+	t.Helper()
+
+	manager, err := neo4go.NewManager(options)
+	if err != nil {
+		t.Fatalf("Could not create the manager : %s", err.FmtError())
+	}
+	t.Cleanup(func() {
+		_ = manager.Close()
+	})
+
+	return manager
+}
+
+func TestStubServer_Query(t *testing.T) {
+	server := NewStubServer(t,
+		ExpectRun("MATCH (u:User) RETURN u.name AS name, u AS user", "name", "user"),
+		ExpectPull(
+			[]interface{}{"Alice", BoltNode(1, []string{"User"}, map[string]interface{}{"age": 30})},
+			[]interface{}{"Bob", BoltNode(2, []string{"User"}, map[string]interface{}{"age": 40})},
+		).WithBookmark("bookmark:1"),
+	)
+	manager := newStubManager(t, server.ManagerOptions())
+
+	result, err := manager.Query(neo4go.QueryParams{Query: "MATCH (u:User) RETURN u.name AS name, u AS user"})
+	if err != nil {
+		t.Fatalf("Query() error = %s", err.FmtError())
+	}
+
+	names := make([]string, 0)
+	for result.Next() {
+		record, recordErr := result.Record()
+		if recordErr != nil {
+			t.Fatalf("Record() error = %s", recordErr.FmtError())
+		}
+		names = append(names, record.Strings["name"])
+
+		if node := record.Nodes["user"]; node == nil || !reflect.DeepEqual(node.Labels(), []string{"User"}) {
+			t.Errorf("Record() user = %v, want a User node", node)
+		}
+	}
+	if !reflect.DeepEqual(names, []string{"Alice", "Bob"}) {
+		t.Errorf("Record() names = %v, want [Alice Bob]", names)
+	}
+
+	if bookmark := manager.LastBookmark(); bookmark != "bookmark:1" {
+		t.Errorf("LastBookmark() = %q, want %q", bookmark, "bookmark:1")
+	}
+
+	var run *BoltMessage
+	for _, message := range server.Received() {
+		if message.Type == BoltRun && message.Query() != connectivityQuery {
+			message := message
+			run = &message
+		}
+	}
+	if run == nil {
+		t.Fatal("Received() has no RUN message")
+	}
+	if mode := run.Metadata()["mode"]; mode != "r" {
+		t.Errorf("RUN mode = %v, want r", mode)
+	}
+	if db := run.Metadata()["db"]; db != "neo4j" {
+		t.Errorf("RUN db = %v, want neo4j", db)
+	}
+}
+
+func TestStubServer_Transaction(t *testing.T) {
+	server := NewStubServer(t,
+		ExpectBegin(),
+		ExpectRun("CREATE (u:User {name: $name})"),
+		ExpectPull(),
+		ExpectCommit("bookmark:2"),
+	)
+	manager := newStubManager(t, server.ManagerOptions())
+
+	txID, err := manager.BeginTransaction(neo4go.TransactionParams{IsWrite: true})
+	if err != nil {
+		t.Fatalf("BeginTransaction() error = %s", err.FmtError())
+	}
+
+	name := "Alice"
+	result, err := manager.Query(neo4go.QueryParams{
+		Query:       "CREATE (u:User {name: $name})",
+		Params:      map[string]neo4go.InputStruct{"name": neo4go.NewInputString(&name)},
+		Transaction: txID,
+	})
+	if err != nil {
+		t.Fatalf("Query() error = %s", err.FmtError())
+	}
+	_ = result.Close()
+
+	if err := manager.Commit(txID); err != nil {
+		t.Fatalf("Commit() error = %s", err.FmtError())
+	}
+	if bookmark := manager.LastBookmark(); bookmark != "bookmark:2" {
+		t.Errorf("LastBookmark() = %q, want %q", bookmark, "bookmark:2")
+	}
+
+	for _, message := range server.Received() {
+		if message.Type == BoltRun && message.Query() != connectivityQuery {
+			if name := message.Params()["name"]; name != "Alice" {
+				t.Errorf("RUN params name = %v, want Alice", name)
+			}
+		}
+	}
+}
+
+func TestStubServer_TransactionConfig(t *testing.T) {
+	server := NewStubServer(t,
+		ExpectBegin(),
+		ExpectRun("CREATE (u:User)"),
+		ExpectPull(),
+		ExpectCommit(""),
+	)
+	appName, requestID := "billing", "42"
+	options := server.ManagerOptions()
+	options.DefaultMetadata = map[string]neo4go.InputStruct{"app": neo4go.NewInputString(&appName)}
+	manager := newStubManager(t, options)
+
+	txID, err := manager.BeginTransaction(neo4go.TransactionParams{
+		IsWrite:  true,
+		Timeout:  5 * time.Second,
+		Metadata: map[string]neo4go.InputStruct{"request": neo4go.NewInputString(&requestID)},
+	})
+	if err != nil {
+		t.Fatalf("BeginTransaction() error = %s", err.FmtError())
+	}
+	if _, err := manager.Query(neo4go.QueryParams{Query: "CREATE (u:User)", Transaction: txID, CommitOnSuccess: true}); err != nil {
+		t.Fatalf("Query() error = %s", err.FmtError())
+	}
+
+	for _, message := range server.Received() {
+		if message.Type != BoltBegin {
+			continue
+		}
+		if timeout := message.Metadata()["tx_timeout"]; timeout != int64(5000) {
+			t.Errorf("BEGIN tx_timeout = %v, want 5000", timeout)
+		}
+		wantMetadata := map[string]interface{}{"app": "billing", "request": "42"}
+		if metadata := message.Metadata()["tx_metadata"]; !reflect.DeepEqual(metadata, wantMetadata) {
+			t.Errorf("BEGIN tx_metadata = %v, want %v", metadata, wantMetadata)
+		}
+	}
+}
+
+func TestStubServer_Routing(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		accessMode neo4go.AccessMode
+		wantWriter bool
+	}{
+		{
+			name:  "Should send a read query to a reader",
+			query: "MATCH (u:User) RETURN u",
+		},
+		{
+			name:       "Should send a write query to a writer",
+			query:      "CREATE (u:User)",
+			wantWriter: true,
+		},
+		{
+			name:  "Should send a read-only procedure call to a reader",
+			query: "CALL db.labels()",
+		},
+		{
+			name:       "Should send a write procedure call to a reader when forced",
+			query:      "CALL db.createLabel('User')",
+			accessMode: neo4go.READ_ACCESS_MODE,
+		},
+		{
+			name:       "Should send a read query to a writer when forced",
+			query:      "MATCH (u:User) RETURN u",
+			accessMode: neo4go.WRITE_ACCESS_MODE,
+			wantWriter: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := NewStubServer(t)
+			writer := NewStubServer(t)
+			router := NewStubServer(t)
+			router.Route([]*StubServer{reader}, []*StubServer{writer})
+
+			target := reader
+			if tt.wantWriter {
+				target = writer
+			}
+			target.Expect(ExpectRun(tt.query), ExpectPull())
+
+			options := router.ManagerOptions()
+			options.URI = router.RoutingURI()
+			manager := newStubManager(t, options)
+
+			if _, err := manager.Query(neo4go.QueryParams{Query: tt.query, AccessMode: tt.accessMode}); err != nil {
+				t.Fatalf("Query() error = %s", err.FmtError())
+			}
+
+			// Bolt only sends the mode of read sessions, as write is the default
+			wantMode := "r"
+			if tt.wantWriter {
+				wantMode = ""
+			}
+			for _, message := range target.Received() {
+				if message.Type != BoltRun || message.Query() != tt.query {
+					continue
+				}
+				if mode, _ := message.Metadata()["mode"].(string); mode != wantMode {
+					t.Errorf("RUN mode = %q, want %q", mode, wantMode)
+				}
+			}
+		})
+	}
+}
+
+func TestStubServer_Failover(t *testing.T) {
+	down := NewStubServer(t)
+	down.Close()
+	standby := NewStubServer(t,
+		ExpectRun("RETURN 2 AS two", "two"),
+		ExpectPull([]interface{}{int64(2)}),
+	)
+
+	options := standby.ManagerOptions()
+	options.URI = down.URI()
+	options.URIs = []string{standby.URI()}
+	manager := newStubManager(t, options)
+
+	if _, err := manager.Query(neo4go.QueryParams{Query: "RETURN 2 AS two"}); err != nil {
+		t.Fatalf("Query() on the standby error = %s", err.FmtError())
+	}
+}
+
+func TestStubServer_Failure(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		isError func(error) bool
+	}{
+		{
+			name:    "Should classify a transient error",
+			code:    "Neo.TransientError.Transaction.DeadlockDetected",
+			isError: neo4go.IsTransientError,
+		},
+		{
+			name:    "Should classify a client error",
+			code:    "Neo.ClientError.Statement.SyntaxError",
+			isError: neo4go.IsClientError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewStubServer(t,
+				ExpectRun("MATCH (n) RETURN n").WillFail(tt.code, "failed"),
+				// The pipelined PULL is ignored, then the next query runs on a clean connection
+				ExpectRun("RETURN 2 AS two", "two"),
+				ExpectPull([]interface{}{int64(2)}),
+			)
+			manager := newStubManager(t, server.ManagerOptions())
+
+			_, err := manager.Query(neo4go.QueryParams{Query: "MATCH (n) RETURN n"})
+			if err == nil || !tt.isError(err) {
+				t.Fatalf("Query() error = %v, want a %s error", err, tt.code)
+			}
+
+			result, err := manager.Query(neo4go.QueryParams{Query: "RETURN 2 AS two"})
+			if err != nil {
+				t.Fatalf("Query() after failure error = %s", err.FmtError())
+			}
+			if !result.Next() {
+				t.Fatalf("Next() after failure = false, error = %v", result.Err())
+			}
+		})
+	}
+}
+
+func TestStubServer_UnexpectedMessage(t *testing.T) {
+	recorder := &recorderT{TB: t}
+	server := NewStubServer(recorder, ExpectBegin())
+
+	manager, err := neo4go.NewManager(server.ManagerOptions())
+	if err != nil {
+		t.Fatalf("NewManager() error = %s", err.FmtError())
+	}
+	defer manager.Close()
+
+	if _, err := manager.Query(neo4go.QueryParams{Query: "MATCH (n) RETURN n"}); err == nil {
+		t.Error("Query() error = nil, want an error for an unexpected message")
+	}
+	if len(recorder.failures) == 0 {
+		t.Error("The unexpected message was not reported")
+	}
+	if server.ExpectationsWereMet() == nil {
+		t.Error("ExpectationsWereMet() = nil, want the BEGIN exchange to be reported")
+	}
+
+	// Clear the script so that the cleanup of the server does not fail the test
+	server.mutex.Lock()
+	server.script = nil
+	server.mutex.Unlock()
+}
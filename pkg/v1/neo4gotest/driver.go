@@ -0,0 +1,131 @@
+package neo4gotest
+
+import (
+	"github.com/UlysseGuyon/neo4go/pkg/v1/neo4go"
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// scriptedDriver is an implementation of neo4go.Driver that answers every query from the expectations of a fake manager
+type scriptedDriver struct {
+	fake *FakeManager
+}
+
+// NewSession creates a new session based on the specified session configuration
+func (d *scriptedDriver) NewSession(config neo4j.SessionConfig) (neo4go.Session, error) {
+	return &scriptedSession{fake: d.fake}, nil
+}
+
+// VerifyConnectivity returns nil if the driver can connect to the database
+func (d *scriptedDriver) VerifyConnectivity() error {
+	return nil
+}
+
+// Close closes the driver and all its underlying connections
+func (d *scriptedDriver) Close() error {
+	return nil
+}
+
+// scriptedSession is an implementation of neo4go.Session that answers every query from the expectations of a fake manager
+type scriptedSession struct {
+	fake *FakeManager
+}
+
+// LastBookmark returns the bookmark received following the last successfully completed transaction
+func (s *scriptedSession) LastBookmark() string {
+	return ""
+}
+
+// BeginTransaction starts a new explicit transaction on this session
+func (s *scriptedSession) BeginTransaction(configurers ...func(*neo4j.TransactionConfig)) (neo4go.Transaction, error) {
+	return &scriptedTransaction{fake: s.fake}, nil
+}
+
+// Run executes an auto-commit query and returns its result
+func (s *scriptedSession) Run(query string, params map[string]interface{}, configurers ...func(*neo4j.TransactionConfig)) (neo4j.Result, error) {
+	return s.fake.run(query, params)
+}
+
+// Close closes any open resources and marks this session as unusable
+func (s *scriptedSession) Close() error {
+	return nil
+}
+
+// scriptedTransaction is an implementation of neo4go.Transaction that answers every query from the expectations of a fake manager
+type scriptedTransaction struct {
+	fake *FakeManager
+}
+
+// Run executes a query in this transaction and returns its result
+func (tx *scriptedTransaction) Run(query string, params map[string]interface{}) (neo4j.Result, error) {
+	return tx.fake.run(query, params)
+}
+
+// Commit commits the transaction
+func (tx *scriptedTransaction) Commit() error {
+	return nil
+}
+
+// Rollback rolls back the transaction
+func (tx *scriptedTransaction) Rollback() error {
+	return nil
+}
+
+// Close rolls back the transaction if it is not already committed or rolled back, and releases its resources
+func (tx *scriptedTransaction) Close() error {
+	return nil
+}
+
+// scriptedResult is an in-memory implementation of neo4j.Result over the records of an expectation
+type scriptedResult struct {
+	records      []neo4j.Record
+	currentIndex int
+}
+
+// newScriptedResult creates a new result that iterates over the given records
+func newScriptedResult(records []neo4j.Record) *scriptedResult {
+	return &scriptedResult{records: records, currentIndex: -1}
+}
+
+// Keys returns the keys available on the result set.
+func (res *scriptedResult) Keys() ([]string, error) {
+	if len(res.records) == 0 {
+		return []string{}, nil
+	}
+
+	return res.records[0].Keys(), nil
+}
+
+// Next returns true only if there is a record to be processed.
+func (res *scriptedResult) Next() bool {
+	if res.currentIndex < len(res.records) {
+		res.currentIndex++
+	}
+
+	return res.currentIndex < len(res.records)
+}
+
+// Err returns the latest error that caused this Next to return false.
+func (res *scriptedResult) Err() error {
+	return nil
+}
+
+// Record returns the current record.
+func (res *scriptedResult) Record() neo4j.Record {
+	if res.currentIndex < 0 || res.currentIndex >= len(res.records) {
+		return nil
+	}
+
+	return res.records[res.currentIndex]
+}
+
+// Summary returns the summary information about the statement execution.
+func (res *scriptedResult) Summary() (neo4j.ResultSummary, error) {
+	return nil, nil
+}
+
+// Consume consumes the entire result and returns the summary information
+// about the statement execution.
+func (res *scriptedResult) Consume() (neo4j.ResultSummary, error) {
+	res.currentIndex = len(res.records)
+	return nil, nil
+}
@@ -0,0 +1,26 @@
+package neo4gotest
+
+import (
+	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
+	"github.com/UlysseGuyon/neo4go/pkg/v1/neo4go"
+)
+
+// NewClientError creates a neo4go Client error, as returned for an invalid query
+func NewClientError(err string) neo4go.Neo4GoError {
+	return &internalErr.ClientError{Err: err}
+}
+
+// NewTransientError creates a neo4go Transient error, which makes managed transactions retry
+func NewTransientError(err string) neo4go.Neo4GoError {
+	return &internalErr.TransientError{Err: err}
+}
+
+// NewSessionError creates a neo4go Session error, which makes managed transactions retry
+func NewSessionError(err string) neo4go.Neo4GoError {
+	return &internalErr.SessionError{Err: err}
+}
+
+// NewUnavailableError creates a neo4go Service Unavailable error, which makes managed transactions retry
+func NewUnavailableError(err string) neo4go.Neo4GoError {
+	return &internalErr.UnavailableError{Err: err}
+}
@@ -0,0 +1,103 @@
+package neo4gotest
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"github.com/UlysseGuyon/neo4go/pkg/v1/neo4go"
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// Expectation represents a query that the fake manager expects to run, and the way it responds to it
+type Expectation struct {
+	// The exact query text expected, used if pattern is nil
+	query string
+
+	// The pattern that the query text must match, if the expectation was created from a regex
+	pattern *regexp.Regexp
+
+	// The expected params, already converted as raw values. Params are not checked if nil
+	params map[string]interface{}
+
+	// The records returned when the expectation is met
+	records []neo4j.Record
+
+	// The error returned when the expectation is met
+	err error
+
+	// Tells if a query already met this expectation
+	met bool
+}
+
+// WithParams sets the params expected with the query. Each value is encoded with the default neo4go encoder,
+// so plain go values, tagged structs and neo4go query inputs are all accepted
+func (e *Expectation) WithParams(params map[string]interface{}) *Expectation {
+	encoder := neo4go.NewEncoder(nil)
+
+	e.params = make(map[string]interface{})
+	for key, value := range params {
+		e.params[key] = neo4go.ConvertInput(encoder.Encode(value))
+	}
+
+	return e
+}
+
+// WillReturnRecords sets the records returned by the query, built from go literals.
+// Integers and floats are converted as int64 and float64, like the values returned by the neo4j-go-driver
+func (e *Expectation) WillReturnRecords(records ...map[string]interface{}) *Expectation {
+	e.records = make([]neo4j.Record, 0, len(records))
+	for _, record := range records {
+		e.records = append(e.records, newRecord(record))
+	}
+
+	return e
+}
+
+// WillReturnError sets the error returned when running the query
+func (e *Expectation) WillReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// matches tells if the query and its raw params meet this expectation
+func (e *Expectation) matches(query string, params map[string]interface{}) bool {
+	if e.pattern != nil {
+		if !e.pattern.MatchString(query) {
+			return false
+		}
+	} else if e.query != query {
+		return false
+	}
+
+	if e.params == nil {
+		return true
+	}
+
+	// A query without params is sent with an empty map by the manager
+	if len(e.params) == 0 && len(params) == 0 {
+		return true
+	}
+
+	return reflect.DeepEqual(e.params, params)
+}
+
+// String returns a readable description of the expectation
+func (e *Expectation) String() string {
+	description := fmt.Sprintf("query %q", e.query)
+	if e.pattern != nil {
+		description = fmt.Sprintf("query matching %q", e.pattern.String())
+	}
+
+	if e.params != nil {
+		keys := make([]string, 0, len(e.params))
+		for key := range e.params {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		description = fmt.Sprintf("%s with params %v", description, keys)
+	}
+
+	return description
+}
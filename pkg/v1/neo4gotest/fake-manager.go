@@ -0,0 +1,140 @@
+package neo4gotest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	internalErr "github.com/UlysseGuyon/neo4go/internal/errors"
+	"github.com/UlysseGuyon/neo4go/pkg/v1/neo4go"
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// FakeManager is an implementation of neo4go.Manager that runs in memory. Every query it runs,
+// in a transaction or not, must meet one of its expectations, which provides the records to return.
+// It is a real manager running on a scripted driver, so transactions and result handling behave like in production
+type FakeManager struct {
+	neo4go.Manager
+
+	// The test that uses this manager
+	t testing.TB
+
+	// All the expectations of this manager, in the order they were added
+	expectations []*Expectation
+
+	// The mutex used to prevent concurrent access to the expectations
+	mutex sync.Mutex
+}
+
+// NewFakeManager creates a new instance of FakeManager, with a given config. A nil config will result in the default config being applied.
+// The URI, auth and driver configurers of the config are not used. At the end of the test, it fails if any expectation was not met
+func NewFakeManager(t testing.TB, options *neo4go.ManagerOptions) *FakeManager {
+	t.Helper()
+
+	// Use the given config if not nil
+	usedOpt := neo4go.ManagerOptions{}
+	if options != nil {
+		usedOpt = *options
+	}
+
+	// Fill the connection fields that are required by the manager but not used by the scripted driver
+	if usedOpt.URI == "" {
+		usedOpt.URI = "bolt://neo4gotest"
+	}
+	if usedOpt.DatabaseName == "" {
+		usedOpt.DatabaseName = "neo4j"
+	}
+
+	fake := &FakeManager{t: t}
+
+	manager, err := neo4go.NewManagerWithDriver(usedOpt, &scriptedDriver{fake: fake})
+	if err != nil {
+		t.Fatalf("Could not create the fake manager : %s", err.FmtError())
+	}
+	fake.Manager = manager
+
+	t.Cleanup(func() {
+		fake.AssertExpectations()
+		_ = fake.Close()
+	})
+
+	return fake
+}
+
+// ExpectQuery adds an expectation for a query that has exactly the given text
+func (fake *FakeManager) ExpectQuery(query string) *Expectation {
+	expectation := &Expectation{query: query}
+
+	fake.mutex.Lock()
+	fake.expectations = append(fake.expectations, expectation)
+	fake.mutex.Unlock()
+
+	return expectation
+}
+
+// ExpectQueryRegex adds an expectation for a query whose text matches the given regular expression
+func (fake *FakeManager) ExpectQueryRegex(pattern string) *Expectation {
+	expectation := &Expectation{query: pattern, pattern: regexp.MustCompile(pattern)}
+
+	fake.mutex.Lock()
+	fake.expectations = append(fake.expectations, expectation)
+	fake.mutex.Unlock()
+
+	return expectation
+}
+
+// ExpectationsWereMet returns an error listing all the expectations that were not met yet
+func (fake *FakeManager) ExpectationsWereMet() error {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	unmet := make([]string, 0)
+	for _, expectation := range fake.expectations {
+		if !expectation.met {
+			unmet = append(unmet, expectation.String())
+		}
+	}
+
+	if len(unmet) > 0 {
+		return fmt.Errorf("%d expectation(s) were not met : %s", len(unmet), strings.Join(unmet, ", "))
+	}
+
+	return nil
+}
+
+// AssertExpectations fails the test if any expectation was not met yet
+func (fake *FakeManager) AssertExpectations() {
+	fake.t.Helper()
+
+	if err := fake.ExpectationsWereMet(); err != nil {
+		fake.t.Error(err)
+	}
+}
+
+// run finds the first unmet expectation matching the query and returns its records or error.
+// A query that does not match any expectation fails the test
+func (fake *FakeManager) run(query string, params map[string]interface{}) (neo4j.Result, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	for _, expectation := range fake.expectations {
+		if expectation.met || !expectation.matches(query, params) {
+			continue
+		}
+
+		expectation.met = true
+		if expectation.err != nil {
+			return nil, expectation.err
+		}
+
+		return newScriptedResult(expectation.records), nil
+	}
+
+	fake.t.Errorf("Unexpected query : %q", query)
+
+	return nil, &internalErr.QueryError{
+		Err: fmt.Sprintf("Unexpected query : %q", query),
+	}
+}
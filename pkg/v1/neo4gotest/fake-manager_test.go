@@ -0,0 +1,156 @@
+package neo4gotest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/UlysseGuyon/neo4go/pkg/v1/neo4go"
+)
+
+// recorderT is a testing.TB that records its failures instead of failing the test
+type recorderT struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorderT) Error(args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprint(args...))
+}
+
+func (r *recorderT) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+type user struct {
+	Name string `neo4j:"name"`
+	Age  int    `neo4j:"age"`
+}
+
+func TestFakeManager_ExpectQuery(t *testing.T) {
+	tests := []struct {
+		name         string
+		expect       func(*FakeManager)
+		query        neo4go.QueryParams
+		wantQueryErr bool
+		wantFailures int
+	}{
+		{
+			name: "Should match an exact query and return its records",
+			expect: func(fake *FakeManager) {
+				fake.ExpectQuery("MATCH (u:User) RETURN u.name AS name").
+					WillReturnRecords(map[string]interface{}{"name": "Alice"})
+			},
+			query: neo4go.QueryParams{Query: "MATCH (u:User) RETURN u.name AS name"},
+		},
+		{
+			name: "Should match a query by regex",
+			expect: func(fake *FakeManager) {
+				fake.ExpectQueryRegex(`^MATCH \(u:User\)`).
+					WillReturnRecords(map[string]interface{}{"name": "Alice"})
+			},
+			query: neo4go.QueryParams{Query: "MATCH (u:User) RETURN u.name AS name"},
+		},
+		{
+			name: "Should match the encoded params",
+			expect: func(fake *FakeManager) {
+				fake.ExpectQuery("CREATE (u:User $user)").
+					WithParams(map[string]interface{}{"user": user{Name: "Alice", Age: 30}})
+			},
+			query: neo4go.QueryParams{
+				Query: "CREATE (u:User $user)",
+				Params: map[string]neo4go.InputStruct{
+					"user": neo4go.NewEncoder(nil).Encode(user{Name: "Alice", Age: 30}),
+				},
+			},
+		},
+		{
+			name: "Should fail on different params",
+			expect: func(fake *FakeManager) {
+				fake.ExpectQuery("CREATE (u:User $user)").
+					WithParams(map[string]interface{}{"user": user{Name: "Bob", Age: 30}})
+			},
+			query: neo4go.QueryParams{
+				Query: "CREATE (u:User $user)",
+				Params: map[string]neo4go.InputStruct{
+					"user": neo4go.NewEncoder(nil).Encode(user{Name: "Alice", Age: 30}),
+				},
+			},
+			wantQueryErr: true,
+			wantFailures: 2,
+		},
+		{
+			name: "Should return the configured error",
+			expect: func(fake *FakeManager) {
+				fake.ExpectQuery("MATCH (u:User) RETURN u").WillReturnError(NewClientError("Invalid syntax"))
+			},
+			query:        neo4go.QueryParams{Query: "MATCH (u:User) RETURN u"},
+			wantQueryErr: true,
+		},
+		{
+			name:         "Should fail on an unexpected query",
+			expect:       func(fake *FakeManager) {},
+			query:        neo4go.QueryParams{Query: "MATCH (u:User) RETURN u"},
+			wantQueryErr: true,
+			wantFailures: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &recorderT{TB: t}
+			fake := NewFakeManager(recorder, nil)
+			tt.expect(fake)
+
+			_, err := fake.Query(tt.query)
+			if (err != nil) != tt.wantQueryErr {
+				t.Errorf("FakeManager.Query() error = %v, wantErr %v", err, tt.wantQueryErr)
+			}
+
+			fake.AssertExpectations()
+			if len(recorder.failures) != tt.wantFailures {
+				t.Errorf("FakeManager failures = %v, want %d failures", recorder.failures, tt.wantFailures)
+			}
+		})
+	}
+}
+
+func TestFakeManager_Records(t *testing.T) {
+	fake := NewFakeManager(t, nil)
+	fake.ExpectQuery("MATCH (u:User) RETURN u, u.age AS age").
+		WillReturnRecords(
+			map[string]interface{}{"u": NewNode(1, []string{"User"}, map[string]interface{}{"name": "Alice", "age": 30}), "age": 30},
+		)
+
+	record, err := neo4go.Single(fake.Query(neo4go.QueryParams{Query: "MATCH (u:User) RETURN u, u.age AS age"}))
+	if err != nil {
+		t.Fatalf("FakeManager.Query() error = %v", err)
+	}
+
+	if record.Ints["age"] != 30 {
+		t.Errorf("FakeManager.Query() age = %v, want 30", record.Ints["age"])
+	}
+
+	decoded := user{}
+	if err := record.DecodeNode(nil, "u", &decoded); err != nil {
+		t.Fatalf("RecordMap.DecodeNode() error = %v", err)
+	}
+	if decoded.Name != "Alice" || decoded.Age != 30 {
+		t.Errorf("RecordMap.DecodeNode() = %+v, want Alice aged 30", decoded)
+	}
+}
+
+func TestFakeManager_WriteTransaction(t *testing.T) {
+	fake := NewFakeManager(t, &neo4go.ManagerOptions{
+		Retry: neo4go.RetryOptions{InitialDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	})
+	fake.ExpectQuery("CREATE (u:User)").WillReturnError(NewTransientError("Deadlock detected"))
+	fake.ExpectQuery("CREATE (u:User)")
+
+	err := fake.WriteTransaction(neo4go.TransactionParams{}, func(tx neo4go.Tx) error {
+		_, err := tx.Query(neo4go.QueryParams{Query: "CREATE (u:User)"})
+		return err
+	})
+	if err != nil {
+		t.Errorf("FakeManager.WriteTransaction() error = %v", err)
+	}
+}
@@ -0,0 +1,395 @@
+package neo4gotest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// The PackStream markers used to encode and decode Bolt messages
+const (
+	packNull    = 0xC0
+	packFloat   = 0xC1
+	packFalse   = 0xC2
+	packTrue    = 0xC3
+	packInt8    = 0xC8
+	packInt16   = 0xC9
+	packInt32   = 0xCA
+	packInt64   = 0xCB
+	packBytes8  = 0xCC
+	packBytes16 = 0xCD
+	packBytes32 = 0xCE
+	packString8 = 0xD0
+	packStr16   = 0xD1
+	packStr32   = 0xD2
+	packList8   = 0xD4
+	packList16  = 0xD5
+	packList32  = 0xD6
+	packMap8    = 0xD8
+	packMap16   = 0xD9
+	packMap32   = 0xDA
+
+	packTinyString = 0x80
+	packTinyList   = 0x90
+	packTinyMap    = 0xA0
+	packTinyStruct = 0xB0
+)
+
+// BoltStruct represents a PackStream structure, such as a Bolt message or a graph value (node, relationship...)
+type BoltStruct struct {
+	// The tag byte identifying the type of the structure
+	Tag byte
+
+	// The fields of the structure
+	Fields []interface{}
+}
+
+// BoltNode creates the PackStream structure of a node, that can be sent in the records of a stub server
+func BoltNode(id int64, labels []string, props map[string]interface{}) BoltStruct {
+	rawLabels := make([]interface{}, 0, len(labels))
+	for _, label := range labels {
+		rawLabels = append(rawLabels, label)
+	}
+
+	return BoltStruct{Tag: 'N', Fields: []interface{}{id, rawLabels, props}}
+}
+
+// BoltRelationship creates the PackStream structure of a relationship, that can be sent in the records of a stub server
+func BoltRelationship(id int64, startID int64, endID int64, relType string, props map[string]interface{}) BoltStruct {
+	return BoltStruct{Tag: 'R', Fields: []interface{}{id, startID, endID, relType, props}}
+}
+
+// packer encodes go values as PackStream
+type packer struct {
+	buf bytes.Buffer
+}
+
+// pack encodes any supported go value
+func (p *packer) pack(value interface{}) error {
+	switch typedVal := value.(type) {
+	case nil:
+		p.buf.WriteByte(packNull)
+		return nil
+	case bool:
+		if typedVal {
+			p.buf.WriteByte(packTrue)
+		} else {
+			p.buf.WriteByte(packFalse)
+		}
+		return nil
+	case string:
+		p.packHeader(len(typedVal), packTinyString, packString8, packStr16, packStr32)
+		p.buf.WriteString(typedVal)
+		return nil
+	case []byte:
+		p.packHeader(len(typedVal), -1, packBytes8, packBytes16, packBytes32)
+		p.buf.Write(typedVal)
+		return nil
+	case BoltStruct:
+		p.buf.WriteByte(packTinyStruct + byte(len(typedVal.Fields)))
+		p.buf.WriteByte(typedVal.Tag)
+		for _, field := range typedVal.Fields {
+			if err := p.pack(field); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() {
+			return p.pack(nil)
+		}
+		return p.pack(val.Elem().Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		p.packInt(val.Int())
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		p.packInt(int64(val.Uint()))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		p.buf.WriteByte(packFloat)
+		_ = binary.Write(&p.buf, binary.BigEndian, math.Float64bits(val.Float()))
+		return nil
+	case reflect.Slice, reflect.Array:
+		p.packHeader(val.Len(), packTinyList, packList8, packList16, packList32)
+		for i := 0; i < val.Len(); i++ {
+			if err := p.pack(val.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		p.packHeader(val.Len(), packTinyMap, packMap8, packMap16, packMap32)
+		iter := val.MapRange()
+		for iter.Next() {
+			if err := p.pack(fmt.Sprint(iter.Key().Interface())); err != nil {
+				return err
+			}
+			if err := p.pack(iter.Value().Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("cannot pack value of type %T", value)
+	}
+}
+
+// packInt encodes an integer with the smallest possible marker
+func (p *packer) packInt(value int64) {
+	switch {
+	case value >= -16 && value <= 127:
+		p.buf.WriteByte(byte(int8(value)))
+	case value >= math.MinInt8 && value <= math.MaxInt8:
+		p.buf.WriteByte(packInt8)
+		p.buf.WriteByte(byte(int8(value)))
+	case value >= math.MinInt16 && value <= math.MaxInt16:
+		p.buf.WriteByte(packInt16)
+		_ = binary.Write(&p.buf, binary.BigEndian, int16(value))
+	case value >= math.MinInt32 && value <= math.MaxInt32:
+		p.buf.WriteByte(packInt32)
+		_ = binary.Write(&p.buf, binary.BigEndian, int32(value))
+	default:
+		p.buf.WriteByte(packInt64)
+		_ = binary.Write(&p.buf, binary.BigEndian, value)
+	}
+}
+
+// packHeader encodes the marker and size of a sized value. A negative tiny marker means that the type has no tiny form
+func (p *packer) packHeader(size int, tiny int, marker8 byte, marker16 byte, marker32 byte) {
+	switch {
+	case tiny >= 0 && size < 16:
+		p.buf.WriteByte(byte(tiny + size))
+	case size <= math.MaxUint8:
+		p.buf.WriteByte(marker8)
+		p.buf.WriteByte(byte(size))
+	case size <= math.MaxUint16:
+		p.buf.WriteByte(marker16)
+		_ = binary.Write(&p.buf, binary.BigEndian, uint16(size))
+	default:
+		p.buf.WriteByte(marker32)
+		_ = binary.Write(&p.buf, binary.BigEndian, uint32(size))
+	}
+}
+
+// unpacker decodes PackStream values into go values
+type unpacker struct {
+	reader *bytes.Reader
+}
+
+// unpack decodes the next value. Integers are decoded as int64, lists as []interface{}, maps as map[string]interface{}
+// and structures as BoltStruct
+func (u *unpacker) unpack() (interface{}, error) {
+	marker, err := u.reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case marker < 0x80 || marker >= 0xF0:
+		return int64(int8(marker)), nil
+	case marker&0xF0 == packTinyString:
+		return u.unpackString(int(marker & 0x0F))
+	case marker&0xF0 == packTinyList:
+		return u.unpackList(int(marker & 0x0F))
+	case marker&0xF0 == packTinyMap:
+		return u.unpackMap(int(marker & 0x0F))
+	case marker&0xF0 == packTinyStruct:
+		return u.unpackStruct(int(marker & 0x0F))
+	}
+
+	switch marker {
+	case packNull:
+		return nil, nil
+	case packTrue:
+		return true, nil
+	case packFalse:
+		return false, nil
+	case packFloat:
+		var bits uint64
+		err := binary.Read(u.reader, binary.BigEndian, &bits)
+		return math.Float64frombits(bits), err
+	case packInt8:
+		var value int8
+		err := binary.Read(u.reader, binary.BigEndian, &value)
+		return int64(value), err
+	case packInt16:
+		var value int16
+		err := binary.Read(u.reader, binary.BigEndian, &value)
+		return int64(value), err
+	case packInt32:
+		var value int32
+		err := binary.Read(u.reader, binary.BigEndian, &value)
+		return int64(value), err
+	case packInt64:
+		var value int64
+		err := binary.Read(u.reader, binary.BigEndian, &value)
+		return value, err
+	case packBytes8, packBytes16, packBytes32:
+		size, err := u.readSize(marker, packBytes8, packBytes16)
+		if err != nil {
+			return nil, err
+		}
+		value := make([]byte, size)
+		_, err = io.ReadFull(u.reader, value)
+		return value, err
+	case packString8, packStr16, packStr32:
+		size, err := u.readSize(marker, packString8, packStr16)
+		if err != nil {
+			return nil, err
+		}
+		return u.unpackString(size)
+	case packList8, packList16, packList32:
+		size, err := u.readSize(marker, packList8, packList16)
+		if err != nil {
+			return nil, err
+		}
+		return u.unpackList(size)
+	case packMap8, packMap16, packMap32:
+		size, err := u.readSize(marker, packMap8, packMap16)
+		if err != nil {
+			return nil, err
+		}
+		return u.unpackMap(size)
+	default:
+		return nil, fmt.Errorf("unknown PackStream marker 0x%X", marker)
+	}
+}
+
+// readSize reads the size following a sized marker
+func (u *unpacker) readSize(marker byte, marker8 byte, marker16 byte) (int, error) {
+	switch marker {
+	case marker8:
+		size, err := u.reader.ReadByte()
+		return int(size), err
+	case marker16:
+		var size uint16
+		err := binary.Read(u.reader, binary.BigEndian, &size)
+		return int(size), err
+	default:
+		var size uint32
+		err := binary.Read(u.reader, binary.BigEndian, &size)
+		return int(size), err
+	}
+}
+
+// unpackString decodes a string of the given size
+func (u *unpacker) unpackString(size int) (string, error) {
+	value := make([]byte, size)
+	_, err := io.ReadFull(u.reader, value)
+	return string(value), err
+}
+
+// unpackList decodes a list of the given size
+func (u *unpacker) unpackList(size int) ([]interface{}, error) {
+	list := make([]interface{}, 0, size)
+	for i := 0; i < size; i++ {
+		item, err := u.unpack()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+	}
+
+	return list, nil
+}
+
+// unpackMap decodes a map of the given size
+func (u *unpacker) unpackMap(size int) (map[string]interface{}, error) {
+	resMap := make(map[string]interface{}, size)
+	for i := 0; i < size; i++ {
+		key, err := u.unpack()
+		if err != nil {
+			return nil, err
+		}
+		value, err := u.unpack()
+		if err != nil {
+			return nil, err
+		}
+		resMap[fmt.Sprint(key)] = value
+	}
+
+	return resMap, nil
+}
+
+// unpackStruct decodes a structure of the given size
+func (u *unpacker) unpackStruct(size int) (BoltStruct, error) {
+	tag, err := u.reader.ReadByte()
+	if err != nil {
+		return BoltStruct{}, err
+	}
+
+	fields, err := u.unpackList(size)
+	if err != nil {
+		return BoltStruct{}, err
+	}
+
+	return BoltStruct{Tag: tag, Fields: fields}, nil
+}
+
+// readMessage reads a chunked Bolt message and decodes it as a structure
+func readMessage(reader io.Reader) (BoltStruct, error) {
+	var message bytes.Buffer
+
+	for {
+		var chunkSize uint16
+		if err := binary.Read(reader, binary.BigEndian, &chunkSize); err != nil {
+			return BoltStruct{}, err
+		}
+
+		// An empty chunk ends the message. Empty messages are NOOPs sent to keep the connection alive
+		if chunkSize == 0 {
+			if message.Len() == 0 {
+				continue
+			}
+			break
+		}
+
+		if _, err := io.CopyN(&message, reader, int64(chunkSize)); err != nil {
+			return BoltStruct{}, err
+		}
+	}
+
+	value, err := (&unpacker{reader: bytes.NewReader(message.Bytes())}).unpack()
+	if err != nil {
+		return BoltStruct{}, err
+	}
+
+	structure, isStruct := value.(BoltStruct)
+	if !isStruct {
+		return BoltStruct{}, fmt.Errorf("received a %T instead of a Bolt message", value)
+	}
+
+	return structure, nil
+}
+
+// writeMessage encodes a structure and writes it as a chunked Bolt message
+func writeMessage(writer io.Writer, message BoltStruct) error {
+	p := &packer{}
+	if err := p.pack(message); err != nil {
+		return err
+	}
+
+	var chunked bytes.Buffer
+	data := p.buf.Bytes()
+	for len(data) > 0 {
+		chunkSize := len(data)
+		if chunkSize > math.MaxUint16 {
+			chunkSize = math.MaxUint16
+		}
+
+		_ = binary.Write(&chunked, binary.BigEndian, uint16(chunkSize))
+		chunked.Write(data[:chunkSize])
+		data = data[chunkSize:]
+	}
+	chunked.Write([]byte{0x00, 0x00})
+
+	_, err := writer.Write(chunked.Bytes())
+	return err
+}
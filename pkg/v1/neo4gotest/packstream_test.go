@@ -0,0 +1,62 @@
+package neo4gotest
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_writeMessage_readMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  interface{}
+	}{
+		{name: "Should round trip null", value: nil, want: nil},
+		{name: "Should round trip booleans", value: true, want: true},
+		{name: "Should round trip tiny ints", value: -16, want: int64(-16)},
+		{name: "Should round trip int8", value: -17, want: int64(-17)},
+		{name: "Should round trip int16", value: 1000, want: int64(1000)},
+		{name: "Should round trip int32", value: -100000, want: int64(-100000)},
+		{name: "Should round trip int64", value: int64(math.MaxInt64), want: int64(math.MaxInt64)},
+		{name: "Should round trip floats", value: float32(1.5), want: 1.5},
+		{name: "Should round trip long strings", value: strings.Repeat("a", 300), want: strings.Repeat("a", 300)},
+		{name: "Should round trip bytes", value: []byte{1, 2, 3}, want: []byte{1, 2, 3}},
+		{name: "Should round trip typed lists", value: []string{"a", "b"}, want: []interface{}{"a", "b"}},
+		{
+			name:  "Should round trip maps",
+			value: map[string]int{"a": 1},
+			want:  map[string]interface{}{"a": int64(1)},
+		},
+		{
+			name:  "Should round trip structures",
+			value: BoltNode(1, []string{"User"}, nil),
+			want:  BoltStruct{Tag: 'N', Fields: []interface{}{int64(1), []interface{}{"User"}, map[string]interface{}{}}},
+		},
+		{
+			name:  "Should round trip messages bigger than a chunk",
+			value: strings.Repeat("a", math.MaxUint16+10),
+			want:  strings.Repeat("a", math.MaxUint16+10),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeMessage(&buf, BoltRecord(tt.value)); err != nil {
+				t.Fatalf("writeMessage() error = %v", err)
+			}
+
+			got, err := readMessage(&buf)
+			if err != nil {
+				t.Fatalf("readMessage() error = %v", err)
+			}
+
+			want := BoltStruct{Tag: boltRecord, Fields: []interface{}{[]interface{}{tt.want}}}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("readMessage() = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package neo4gotest
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// newRecord creates a neo4j record from a map of go literals, with its keys sorted alphabetically
+func newRecord(values map[string]interface{}) neo4j.Record {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	recordValues := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		recordValues = append(recordValues, normalizeRecordValue(values[key]))
+	}
+
+	return &record{keys: keys, values: recordValues}
+}
+
+// normalizeRecordValue converts a go literal into the type that the neo4j-go-driver would return for it
+func normalizeRecordValue(value interface{}) interface{} {
+	// Values that are already neo4j types or time values are kept as they are
+	switch value.(type) {
+	case nil, neo4j.Node, neo4j.Relationship, neo4j.Path, []byte:
+		return value
+	}
+
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(val.Uint())
+	case reflect.Float32, reflect.Float64:
+		return val.Float()
+	case reflect.Slice, reflect.Array:
+		resArray := make([]interface{}, 0, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			resArray = append(resArray, normalizeRecordValue(val.Index(i).Interface()))
+		}
+		return resArray
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			return value
+		}
+		resMap := make(map[string]interface{})
+		iter := val.MapRange()
+		for iter.Next() {
+			resMap[iter.Key().String()] = normalizeRecordValue(iter.Value().Interface())
+		}
+		return resMap
+	default:
+		return value
+	}
+}
+
+// record is an in-memory implementation of neo4j.Record
+type record struct {
+	keys   []string
+	values []interface{}
+}
+
+// Keys returns the keys available
+func (r *record) Keys() []string {
+	return r.keys
+}
+
+// Values returns the values
+func (r *record) Values() []interface{} {
+	return r.values
+}
+
+// Get returns the value (if any) corresponding to the given key
+func (r *record) Get(key string) (interface{}, bool) {
+	for i, k := range r.keys {
+		if k == key {
+			return r.values[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// GetByIndex returns the value at given index
+func (r *record) GetByIndex(index int) interface{} {
+	return r.values[index]
+}
+
+// node is an in-memory implementation of neo4j.Node
+type node struct {
+	id     int64
+	labels []string
+	props  map[string]interface{}
+}
+
+// NewNode creates a node that can be returned in the records of an expectation
+func NewNode(id int64, labels []string, props map[string]interface{}) neo4j.Node {
+	normalizedProps := make(map[string]interface{})
+	for key, value := range props {
+		normalizedProps[key] = normalizeRecordValue(value)
+	}
+
+	return &node{id: id, labels: labels, props: normalizedProps}
+}
+
+// Id returns the identity of this Node.
+func (n *node) Id() int64 { return n.id }
+
+// Labels returns the labels attached to this Node.
+func (n *node) Labels() []string { return n.labels }
+
+// Props returns the properties of this Node.
+func (n *node) Props() map[string]interface{} { return n.props }
+
+// relationship is an in-memory implementation of neo4j.Relationship
+type relationship struct {
+	id      int64
+	startID int64
+	endID   int64
+	relType string
+	props   map[string]interface{}
+}
+
+// NewRelationship creates a relationship that can be returned in the records of an expectation
+func NewRelationship(id int64, startID int64, endID int64, relType string, props map[string]interface{}) neo4j.Relationship {
+	normalizedProps := make(map[string]interface{})
+	for key, value := range props {
+		normalizedProps[key] = normalizeRecordValue(value)
+	}
+
+	return &relationship{id: id, startID: startID, endID: endID, relType: relType, props: normalizedProps}
+}
+
+// Id returns the identity of this Relationship.
+func (r *relationship) Id() int64 { return r.id }
+
+// StartId returns the identity of the start node of this Relationship.
+func (r *relationship) StartId() int64 { return r.startID }
+
+// EndId returns the identity of the end node of this Relationship.
+func (r *relationship) EndId() int64 { return r.endID }
+
+// Type returns the type of this Relationship.
+func (r *relationship) Type() string { return r.relType }
+
+// Props returns the properties of this Relationship.
+func (r *relationship) Props() map[string]interface{} { return r.props }